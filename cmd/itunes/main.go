@@ -0,0 +1,552 @@
+// Command itunes is the CLI front-end for the itunes package: ad hoc
+// search/playback from a terminal, plus running the MCP server.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/chew-z/itunes.vim/database"
+	"github.com/chew-z/itunes.vim/itunes"
+	"github.com/chew-z/itunes.vim/logging"
+	mcpserver "github.com/chew-z/itunes.vim/mcp"
+	"github.com/chew-z/itunes.vim/pathutil"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultLogLevel returns ITUNES_LOG_LEVEL if set, else "info", as the
+// -log-level flag's default so the env var works even when the flag is
+// omitted.
+func defaultLogLevel() string {
+	if v := os.Getenv("ITUNES_LOG_LEVEL"); v != "" {
+		return v
+	}
+	return "info"
+}
+
+func main() {
+	logLevel := flag.String("log-level", defaultLogLevel(), "log level: debug, info, warn, error (or ITUNES_LOG_LEVEL)")
+	readOnly := flag.Bool("read-only", os.Getenv("ITUNES_READONLY") != "", "mcp only: open the database read-only and skip schema migrations, so it's safe to run alongside a concurrent itunes-migrate process (or ITUNES_READONLY)")
+	profile := flag.String("profile", "", "open the named profile's database (~/.config/itunes/<name>.db) instead of the default or ITUNES_DB_PATH database")
+	flag.Parse()
+	logging.InitLogger(*logLevel)
+
+	args := flag.Args()
+	if len(args) < 1 {
+		usage()
+		os.Exit(1)
+	}
+
+	if args[0] == "profiles" {
+		runProfiles(args[1:])
+		return
+	}
+
+	dbPath := ""
+	if *profile != "" {
+		p, err := pathutil.ProfileDBPath(*profile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "itunes: resolve profile:", err)
+			os.Exit(1)
+		}
+		dbPath = p
+	}
+
+	var dbErr error
+	if args[0] == "mcp" && *readOnly {
+		dbErr = itunes.InitDatabaseReadOnly(dbPath)
+	} else {
+		dbErr = itunes.InitDatabase(dbPath)
+	}
+	if dbErr != nil {
+		fmt.Fprintln(os.Stderr, "itunes: init database:", dbErr)
+		os.Exit(1)
+	}
+	defer itunes.CloseDatabase()
+
+	ctx := context.Background()
+	switch args[0] {
+	case "search":
+		runSearch(ctx, args[1:])
+	case "play":
+		runPlay(ctx, args[1:])
+	case "np":
+		runNowPlayingCompact(ctx)
+	case "now-playing":
+		runNowPlaying(ctx, args[1:])
+	case "refresh":
+		runRefresh(ctx)
+	case "list-playlists":
+		runListPlaylists()
+	case "list-stations":
+		runListStations()
+	case "list-station-genres":
+		runListStationGenres()
+	case "station-homepage":
+		runStationHomepage(args[1:])
+	case "health":
+		runHealth(ctx)
+	case "rating-stats":
+		runRatingStats()
+	case "top-played":
+		runTopPlayed(args[1:])
+	case "export-stations":
+		runExportStations(args[1:])
+	case "import-stations":
+		runImportStations(args[1:])
+	case "prune-stations":
+		runPruneStations(args[1:])
+	case "optimize":
+		runOptimize()
+	case "remap-persistent-id":
+		runRemapPersistentID(args[1:])
+	case "warm-cache":
+		runWarmCache(args[1:])
+	case "mcp":
+		runMCP()
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: itunes [-log-level debug|info|warn|error] [-read-only] [-profile <name>] <command> [args]
+
+commands:
+  profiles list             list profiles with a database under ~/.config/itunes
+  profiles use <name>       make <name> the default profile for future commands
+  search <query> [--output <file>] [--format json|csv]
+                            search the library, optionally saving results to a file
+  play <playlist> <track>   play a track from a playlist
+  np                        show a compact one-line now-playing status
+  now-playing [--watch] [--interval 2s] [--until-stopped]
+                            show (or repeatedly redraw) now-playing status
+  refresh                   refresh the library cache from Apple Music
+  list-playlists            list user playlists
+  list-stations             list all cached radio stations
+  list-station-genres       list radio station genres with counts
+  station-homepage [-open] <id>  print (and optionally open) a station's homepage
+  health                    check database and Apple Music bridge health
+  rating-stats              show track counts per rating and the starred/unstarred split
+  top-played [-limit 20]    show the most-played tracks and artists
+  export-stations <file>    export radio stations as JSON
+  import-stations [-dedupe] [-upsert] <file>  import radio stations from JSON
+  prune-stations [-dry-run] [-yes]  remove inactive radio stations
+  optimize                  VACUUM and ANALYZE the database to reclaim space
+  remap-persistent-id <old> <new>  point a track at a new Apple Music persistent ID
+  warm-cache [query...]     pre-run queries to populate the search cache; defaults
+                            to ITUNES_WARM_CACHE_QUERIES (comma-separated) if none given
+  mcp                       run the MCP server over stdio (-read-only: open the
+                            database read-only and skip migrations, so it's
+                            safe alongside a concurrent itunes-migrate)`)
+}
+
+// runProfiles dispatches the "profiles" command's list/use subcommands. It
+// runs before the database is opened, since listing or switching profiles
+// shouldn't require (or create) a default database.
+func runProfiles(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "usage: itunes profiles list|use <name>")
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "list":
+		names, err := pathutil.ListProfiles()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "itunes: profiles list:", err)
+			os.Exit(1)
+		}
+		active, _ := pathutil.ActiveProfile()
+		for _, name := range names {
+			if name == active {
+				fmt.Printf("* %s\n", name)
+				continue
+			}
+			fmt.Printf("  %s\n", name)
+		}
+	case "use":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "usage: itunes profiles use <name>")
+			os.Exit(1)
+		}
+		if err := pathutil.SetActiveProfile(args[1]); err != nil {
+			fmt.Fprintln(os.Stderr, "itunes: profiles use:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("active profile set to %q\n", args[1])
+	default:
+		fmt.Fprintln(os.Stderr, "usage: itunes profiles list|use <name>")
+		os.Exit(1)
+	}
+}
+
+func runSearch(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	output := fs.String("output", "", "write results to this file instead of stdout")
+	format := fs.String("format", itunes.ExportFormatJSON, `output format when --output is set: "json" or "csv"`)
+	fs.Parse(args)
+	query := ""
+	if fs.NArg() > 0 {
+		query = fs.Arg(0)
+	}
+	tracks, err := itunes.SearchTracksFromDatabase(query, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: search:", err)
+		os.Exit(1)
+	}
+	if *output != "" {
+		if err := itunes.WriteTracksToFile(*output, tracks, *format); err != nil {
+			fmt.Fprintln(os.Stderr, "itunes: search:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("wrote %d track(s) to %s\n", len(tracks), *output)
+		return
+	}
+	printJSON(tracks)
+}
+
+func runPlay(ctx context.Context, args []string) {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: itunes play <playlist> <track>")
+		os.Exit(1)
+	}
+	result, err := itunes.PlayPlaylistTrackWithStatus(ctx, args[0], args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: play:", err)
+		os.Exit(1)
+	}
+	printJSON(result)
+}
+
+func runNowPlaying(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("now-playing", flag.ExitOnError)
+	watch := fs.Bool("watch", false, "repeatedly redraw a single updating now-playing line")
+	interval := fs.Duration("interval", 2*time.Second, "redraw interval when -watch is set")
+	untilStopped := fs.Bool("until-stopped", false, "with -watch, stop once playback is no longer playing")
+	fs.Parse(args)
+
+	if !*watch {
+		status, err := itunes.GetNowPlaying(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "itunes: now-playing:", err)
+			os.Exit(1)
+		}
+		printJSON(status)
+		return
+	}
+
+	watchNowPlaying(ctx, *interval, *untilStopped)
+}
+
+// watchNowPlaying redraws a single terminal line with the compact
+// now-playing status every interval, until SIGINT/SIGTERM or (with
+// untilStopped) until playback is no longer playing.
+func watchNowPlaying(ctx context.Context, interval time.Duration, untilStopped bool) {
+	sigCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		line, err := itunes.GetNowPlayingCompact(sigCtx)
+		if err == nil {
+			fmt.Printf("\r\033[K%s", line)
+		}
+		if untilStopped {
+			if status, statusErr := itunes.GetNowPlaying(sigCtx); statusErr == nil && status.State == itunes.StateStopped {
+				fmt.Println()
+				return
+			}
+		}
+
+		select {
+		case <-sigCtx.Done():
+			fmt.Println()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runNowPlayingCompact(ctx context.Context) {
+	line, err := itunes.GetNowPlayingCompact(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: np:", err)
+		os.Exit(1)
+	}
+	fmt.Println(line)
+}
+
+func runRefresh(ctx context.Context) {
+	stats, err := itunes.RefreshLibraryCache(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: refresh:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("refreshed library: %d tracks, %d playlists", stats.TrackCount, stats.PlaylistCount)
+	if stats.SkippedTracks > 0 {
+		fmt.Printf(" (%d track(s) skipped)", stats.SkippedTracks)
+	}
+	fmt.Println()
+}
+
+func runListPlaylists() {
+	page, err := itunes.ListPlaylists(nil, false, 0, 0)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: list-playlists:", err)
+		os.Exit(1)
+	}
+	printJSON(page.Playlists)
+}
+
+func runListStations() {
+	stations, err := itunes.ListRadioStations(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: list-stations:", err)
+		os.Exit(1)
+	}
+	printJSON(stations)
+}
+
+func runStationHomepage(args []string) {
+	fs := flag.NewFlagSet("station-homepage", flag.ExitOnError)
+	open := fs.Bool("open", false, "open the homepage in the default browser via macOS `open`")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "usage: itunes station-homepage [-open] <id>")
+		os.Exit(1)
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: station-homepage: invalid station id:", fs.Arg(0))
+		os.Exit(1)
+	}
+
+	station, err := itunes.GetRadioStationByID(id)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: station-homepage:", err)
+		os.Exit(1)
+	}
+	if station.Homepage == "" {
+		fmt.Printf("station %q has no homepage set\n", station.Name)
+		return
+	}
+	fmt.Println(station.Homepage)
+
+	if !*open {
+		return
+	}
+	parsed, err := url.Parse(station.Homepage)
+	if err != nil || parsed.Scheme != "https" {
+		fmt.Fprintf(os.Stderr, "itunes: station-homepage: refusing to open non-https url %q\n", station.Homepage)
+		os.Exit(1)
+	}
+	if err := exec.Command("open", station.Homepage).Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: station-homepage:", err)
+		os.Exit(1)
+	}
+}
+
+func runHealth(ctx context.Context) {
+	printJSON(itunes.GetHealth(ctx))
+}
+
+func runRatingStats() {
+	stats, err := itunes.GetRatingStats()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: rating-stats:", err)
+		os.Exit(1)
+	}
+	printJSON(stats)
+}
+
+func runTopPlayed(args []string) {
+	fs := flag.NewFlagSet("top-played", flag.ExitOnError)
+	limit := fs.Int("limit", itunes.DefaultTopLimit, "max tracks/artists to return")
+	fs.Parse(args)
+
+	top, err := itunes.GetTopPlayed(*limit)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: top-played:", err)
+		os.Exit(1)
+	}
+	printJSON(top)
+}
+
+func runListStationGenres() {
+	genres, err := itunes.ListStationGenres()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: list-station-genres:", err)
+		os.Exit(1)
+	}
+	printJSON(genres)
+}
+
+func runExportStations(args []string) {
+	stations, err := itunes.ExportStations()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: export-stations:", err)
+		os.Exit(1)
+	}
+	export := make([]database.RadioStationExport, 0, len(stations))
+	for _, s := range stations {
+		export = append(export, s.ToExport())
+	}
+	data, _ := json.MarshalIndent(export, "", "  ")
+	if len(args) > 0 {
+		if err := os.WriteFile(args[0], data, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, "itunes: export-stations:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Println(string(data))
+}
+
+func runImportStations(args []string) {
+	fs := flag.NewFlagSet("import-stations", flag.ExitOnError)
+	dedupe := fs.Bool("dedupe", false, "skip near-duplicate URLs (differing only by scheme case or ?app=music)")
+	upsert := fs.Bool("upsert", false, "update an existing station's metadata instead of skipping it when its URL is already cached")
+	lenient := fs.Bool("lenient", false, "exit 0 even if some entries were skipped as invalid")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "usage: itunes import-stations [-dedupe] [-upsert] [-lenient] <file>")
+		os.Exit(1)
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: import-stations:", err)
+		os.Exit(1)
+	}
+	var export []database.RadioStationExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: import-stations:", err)
+		os.Exit(1)
+	}
+
+	stations := make([]database.RadioStation, 0, len(export))
+	var reasons []string
+	for i, e := range export {
+		if err := database.ValidateStationExport(e, i); err != nil {
+			reasons = append(reasons, err.Error())
+			continue
+		}
+		stations = append(stations, e.FromExport())
+	}
+
+	result, err := itunes.ImportRadioStations(stations, database.ImportOptions{Dedupe: *dedupe, Upsert: *upsert})
+	if result != nil && err != nil {
+		fmt.Printf("imported %d, updated %d, skipped %d, duplicates %d, errors %d\n",
+			result.Imported, result.Updated, result.Skipped, result.Duplicates, len(result.Errors))
+		fmt.Fprintln(os.Stderr, "itunes: import-stations:", err)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: import-stations:", err)
+		os.Exit(1)
+	}
+	if len(reasons) > 0 {
+		fmt.Printf("imported %d, updated %d, skipped %d invalid (%s), duplicates %d\n",
+			result.Imported, result.Updated, len(reasons), strings.Join(reasons, "; "), result.Duplicates)
+		if !*lenient {
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Printf("imported %d, updated %d, skipped %d, duplicates %d\n", result.Imported, result.Updated, result.Skipped, result.Duplicates)
+}
+
+func runPruneStations(args []string) {
+	fs := flag.NewFlagSet("prune-stations", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "report how many stations would be removed without deleting them")
+	yes := fs.Bool("yes", false, "skip the confirmation prompt")
+	fs.Parse(args)
+
+	if !*dryRun && !*yes {
+		fmt.Print("Remove all inactive radio stations? [y/N] ")
+		reply, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(reply)) != "y" {
+			fmt.Println("aborted")
+			return
+		}
+	}
+
+	n, err := itunes.DeleteInactiveStations(*dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: prune-stations:", err)
+		os.Exit(1)
+	}
+	if *dryRun {
+		fmt.Printf("would remove %d inactive station(s)\n", n)
+		return
+	}
+	fmt.Printf("removed %d inactive station(s)\n", n)
+}
+
+func runOptimize() {
+	result, err := itunes.OptimizeDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: optimize:", err)
+		os.Exit(1)
+	}
+	printJSON(result)
+}
+
+// runRemapPersistentID handles an Apple Music library rebuild reassigning
+// a track's persistent ID: point the cached track at its new ID so it
+// keeps resolving by it, without losing the track's playlist memberships
+// or metadata (both keyed off the row's internal id, not its persistent
+// ID, so neither is actually at risk).
+func runRemapPersistentID(args []string) {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: itunes remap-persistent-id <old-id> <new-id>")
+		os.Exit(1)
+	}
+	if err := itunes.RemapTrackPersistentID(args[0], args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: remap persistent id:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("remapped %s -> %s\n", args[0], args[1])
+}
+
+// runWarmCache pre-runs the given queries (or, if none are given, the
+// ITUNES_WARM_CACHE_QUERIES config list) to populate the search cache
+// ahead of real traffic.
+func runWarmCache(args []string) {
+	if err := itunes.WarmSearchCache(args); err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: warm cache:", err)
+		os.Exit(1)
+	}
+}
+
+func runMCP() {
+	itunes.MaybeAutoRefresh(context.Background())
+	itunes.MaybeWarmCache()
+	s := mcpserver.NewServer()
+	if err := server.ServeStdio(s); err != nil {
+		fmt.Fprintln(os.Stderr, "itunes: mcp:", err)
+		os.Exit(1)
+	}
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes:", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
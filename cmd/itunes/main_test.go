@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestDefaultLogLevelFallsBackToInfo(t *testing.T) {
+	t.Setenv("ITUNES_LOG_LEVEL", "")
+	if got := defaultLogLevel(); got != "info" {
+		t.Fatalf("got %q, want %q", got, "info")
+	}
+}
+
+func TestDefaultLogLevelReadsEnvVar(t *testing.T) {
+	t.Setenv("ITUNES_LOG_LEVEL", "debug")
+	if got := defaultLogLevel(); got != "debug" {
+		t.Fatalf("got %q, want %q", got, "debug")
+	}
+}
+
+func TestLogLevelFlagOverridesEnvVar(t *testing.T) {
+	t.Setenv("ITUNES_LOG_LEVEL", "debug")
+	fs := flag.NewFlagSet("itunes", flag.ContinueOnError)
+	logLevel := fs.String("log-level", defaultLogLevel(), "log level")
+	if err := fs.Parse([]string{"-log-level", "warn", "search", "foo"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if *logLevel != "warn" {
+		t.Fatalf("got %q, want %q", *logLevel, "warn")
+	}
+	if got := fs.Args(); len(got) != 2 || got[0] != "search" || got[1] != "foo" {
+		t.Fatalf("got %v, want [search foo]", got)
+	}
+}
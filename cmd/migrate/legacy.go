@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// JSONTrack is a single entry in the legacy iTunes_Library_Cache.txt/
+// library.json format written by the original vimscript plugin's refresh
+// job, predating the structured RefreshResponse payload.
+type JSONTrack struct {
+	Collection  string `json:"collection"`
+	Artist      string `json:"artist"`
+	Album       string `json:"album"`
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	IsStreaming bool   `json:"is_streaming"`
+	StreamURL   string `json:"stream_url"`
+}
+
+// MigrateFromJSON reads a legacy library.json file (a flat array of
+// JSONTrack) and populates db from it. This supports one-time migration
+// off the original vimscript cache format.
+func MigrateFromJSON(db *database.DatabaseManager, path string) (*RefreshStats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read %q: %w", path, err)
+	}
+	var legacy []JSONTrack
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, fmt.Errorf("migrate: parse %q: %w", path, err)
+	}
+
+	resp := &RefreshResponse{}
+	playlistTracks := map[string][]string{}
+	for i, lt := range legacy {
+		persistentID := fmt.Sprintf("legacy-%d", i)
+		resp.Tracks = append(resp.Tracks, RefreshTrack{
+			PersistentID: persistentID,
+			Name:         lt.Name,
+			Artist:       lt.Artist,
+			Album:        lt.Album,
+			TrackKind:    lt.Kind,
+			IsStreaming:  lt.IsStreaming,
+			StreamURL:    lt.StreamURL,
+		})
+		playlistTracks[lt.Collection] = append(playlistTracks[lt.Collection], persistentID)
+	}
+	for name, ids := range playlistTracks {
+		resp.Playlists = append(resp.Playlists, RefreshPlaylist{
+			PersistentID: "legacy-playlist-" + name,
+			Name:         name,
+			TrackIDs:     ids,
+		})
+	}
+
+	return populateFromRefreshResponse(db, resp, false)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func TestMigrateFromJSONPreservesStreamingTracks(t *testing.T) {
+	legacy := []JSONTrack{
+		{Collection: "Radio", Name: "Local Song", Artist: "Some Artist"},
+		{Collection: "Radio", Name: "Stream Song", Artist: "Some Artist", IsStreaming: true, StreamURL: "https://example.com/stream", Kind: "URL Track"},
+	}
+	data, err := json.Marshal(legacy)
+	if err != nil {
+		t.Fatalf("marshal legacy fixture: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "library.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write legacy fixture: %v", err)
+	}
+
+	db, err := database.NewDatabaseManager("file:itunes_migrate_legacy_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := MigrateFromJSON(db, path); err != nil {
+		t.Fatalf("MigrateFromJSON: %v", err)
+	}
+
+	streamingOnly := true
+	tracks, err := database.NewSearchManager(db).SearchTracks("", &database.SearchFilters{StreamingOnly: &streamingOnly})
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].Name != "Stream Song" {
+		t.Fatalf("got %+v, want only Stream Song", tracks)
+	}
+	if tracks[0].StreamURL != "https://example.com/stream" {
+		t.Fatalf("StreamURL = %q, want preserved URL", tracks[0].StreamURL)
+	}
+}
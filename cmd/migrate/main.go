@@ -0,0 +1,181 @@
+// Command itunes-migrate populates the SQLite library cache from the JXA
+// library-scan script's output, or from a legacy library.json file.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/chew-z/itunes.vim/database"
+	"github.com/chew-z/itunes.vim/logging"
+	"github.com/chew-z/itunes.vim/pathutil"
+)
+
+func main() {
+	dbPath := flag.String("db", "", "path to the library database (default: resolved via ITUNES_DB_PATH)")
+	jsonPath := flag.String("json", "", "migrate from a legacy library.json file instead of running the refresh script")
+	cacheDirFlag := flag.String("cache", "", "cache directory for the raw refresh script output (default: resolved via ITUNES_CACHE_DIR)")
+	stdin := flag.Bool("stdin", false, "read a RefreshResponse JSON document from stdin instead of running the refresh script (useful in CI/non-macOS environments)")
+	validate := flag.Bool("validate", false, "validate the database and exit")
+	format := flag.String("format", "text", `output format for -validate: "text" (default) or "json"`)
+	parallel := flag.Bool("parallel", false, "resolve artist/genre names with a worker pool instead of one at a time (faster for large libraries)")
+	workers := flag.Int("workers", database.DefaultPopulateWorkers, "worker count for -parallel")
+	merge := flag.Bool("merge", false, "keep each scanned playlist's existing track associations and only add to them, instead of replacing membership outright (the default)")
+	flag.Parse()
+
+	logging.InitLogger("info")
+
+	db, err := database.NewDatabaseManager(*dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes-migrate:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if *validate {
+		if _, err := validateDatabase(db, *format); err != nil {
+			fmt.Fprintln(os.Stderr, "itunes-migrate: validate:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *jsonPath != "" {
+		stats, err := MigrateFromJSON(db, *jsonPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "itunes-migrate:", err)
+			os.Exit(1)
+		}
+		printStats(stats)
+		return
+	}
+
+	var resp *RefreshResponse
+	if *stdin {
+		resp, err = readRefreshResponseFromReader(os.Stdin)
+	} else {
+		resp, err = runRefreshScript(*cacheDirFlag)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes-migrate:", err)
+		os.Exit(1)
+	}
+	var stats *RefreshStats
+	if *parallel {
+		stats, err = db.PopulateFromRefreshScriptParallelWithOptions(resp, *workers, database.PopulateOptions{MergePlaylists: *merge})
+	} else {
+		stats, err = populateFromRefreshResponse(db, resp, *merge)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "itunes-migrate:", err)
+		os.Exit(1)
+	}
+	printStats(stats)
+}
+
+// scriptTrack is a single entry in iTunes_Search2_fzf.js's flat JSON
+// output: {id, name, album, collection, artist, downloaded, date_added},
+// where "collection" is the playlist the track was scanned from,
+// "downloaded" reflects whether the script could resolve a local file
+// location for the track, and "date_added" is Apple Music's addedDate as
+// an ISO 8601 string (JXA's JSON.stringify renders JS Date values that
+// way).
+type scriptTrack struct {
+	ID         json.Number `json:"id"`
+	Name       string      `json:"name"`
+	Album      string      `json:"album"`
+	Collection string      `json:"collection"`
+	Artist     string      `json:"artist"`
+	Downloaded bool        `json:"downloaded"`
+	DateAdded  string      `json:"date_added"`
+}
+
+// runRefreshScript executes the JXA library-scan script and converts its
+// flat per-track JSON stdout into a RefreshResponse grouped by playlist. A
+// copy of the raw output is written to the resolved cache directory so a
+// failed parse or import can be retried without re-scanning the library.
+func runRefreshScript(cacheDirFlag string) (*RefreshResponse, error) {
+	cmd := exec.Command("osascript", "-l", "JavaScript", "autoload/iTunes_Search2_fzf.js", "Online")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("run refresh script: %w", err)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("refresh script produced no output (likely killed before finishing)")
+	}
+	if cacheDir, cacheErr := pathutil.ResolveCacheDir(cacheDirFlag); cacheErr == nil {
+		_ = os.WriteFile(filepath.Join(cacheDir, "last-refresh.json"), out, 0o644)
+	}
+	var tracks []scriptTrack
+	if err := json.Unmarshal(out, &tracks); err != nil {
+		return nil, fmt.Errorf("parse refresh script output: %w (raw: %q)", err, truncateForError(out, 200))
+	}
+	return refreshResponseFromScriptTracks(tracks), nil
+}
+
+// refreshResponseFromScriptTracks groups a flat scriptTrack list into the
+// RefreshResponse shape PopulateFromRefreshScript expects, using each
+// track's Apple Music id as its persistent id and its collection as the
+// playlist it belongs to.
+func refreshResponseFromScriptTracks(tracks []scriptTrack) *RefreshResponse {
+	resp := &RefreshResponse{}
+	playlistTracks := map[string][]string{}
+	for _, st := range tracks {
+		persistentID := st.ID.String()
+		dateAdded, _ := time.Parse(time.RFC3339, st.DateAdded)
+		resp.Tracks = append(resp.Tracks, RefreshTrack{
+			PersistentID: persistentID,
+			Name:         st.Name,
+			Artist:       st.Artist,
+			Album:        st.Album,
+			Downloaded:   st.Downloaded,
+			DateAdded:    dateAdded,
+		})
+		playlistTracks[st.Collection] = append(playlistTracks[st.Collection], persistentID)
+	}
+	for name, ids := range playlistTracks {
+		resp.Playlists = append(resp.Playlists, RefreshPlaylist{
+			PersistentID: "playlist-" + name,
+			Name:         name,
+			TrackIDs:     ids,
+		})
+	}
+	return resp
+}
+
+// readRefreshResponseFromReader reads and validates a RefreshResponse JSON
+// document from r. This is the -stdin path: it bypasses osascript
+// entirely, which makes the migration logic testable on non-macOS CI and
+// lets callers supply tracks from custom extraction pipelines.
+func readRefreshResponseFromReader(r io.Reader) (*RefreshResponse, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read stdin: %w", err)
+	}
+	var resp RefreshResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse stdin as RefreshResponse: %w (raw: %q)", err, truncateForError(data, 200))
+	}
+	return &resp, nil
+}
+
+// truncateForError shortens raw script output for inclusion in an error
+// message, so a failed parse of partial/truncated JSON doesn't dump an
+// enormous payload to the terminal.
+func truncateForError(b []byte, n int) string {
+	if len(b) <= n {
+		return string(b)
+	}
+	return string(b[:n]) + "..."
+}
+
+func printStats(stats *RefreshStats) {
+	fmt.Printf("migrated %d tracks across %d playlists (%d skipped) in %s\n",
+		stats.TrackCount, stats.PlaylistCount, stats.SkippedTracks, stats.ProcessingTime)
+}
@@ -0,0 +1,20 @@
+package main
+
+import "github.com/chew-z/itunes.vim/database"
+
+// RefreshTrack, RefreshPlaylist, RefreshStats, and RefreshResponse alias the
+// database package's shapes so the rest of this command doesn't need a
+// package-qualified name for types it uses constantly.
+type (
+	RefreshTrack    = database.RefreshTrack
+	RefreshPlaylist = database.RefreshPlaylist
+	RefreshStats    = database.RefreshStats
+	RefreshResponse = database.RefreshResponse
+)
+
+// populateFromRefreshResponse upserts resp into db, replacing each scanned
+// playlist's track membership unless merge is set. See
+// database.PopulateFromRefreshScript for the implementation.
+func populateFromRefreshResponse(db *database.DatabaseManager, resp *RefreshResponse, merge bool) (*RefreshStats, error) {
+	return db.PopulateFromRefreshScriptWithOptions(resp, database.PopulateOptions{MergePlaylists: merge})
+}
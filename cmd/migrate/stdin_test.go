@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func TestReadRefreshResponseFromReader(t *testing.T) {
+	payload := `{
+		"tracks": [
+			{"persistent_id": "p1", "name": "Song One", "artist": "Artist"}
+		],
+		"playlists": [
+			{"persistent_id": "pl1", "name": "Favorites", "track_ids": ["p1"]}
+		]
+	}`
+
+	resp, err := readRefreshResponseFromReader(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("readRefreshResponseFromReader: %v", err)
+	}
+
+	db, err := database.NewDatabaseManager("file:itunes_migrate_stdin_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	defer db.Close()
+
+	stats, err := populateFromRefreshResponse(db, resp, false)
+	if err != nil {
+		t.Fatalf("populateFromRefreshResponse: %v", err)
+	}
+	if stats.TrackCount != 1 {
+		t.Fatalf("TrackCount = %d, want 1", stats.TrackCount)
+	}
+	if stats.PlaylistCount != 1 {
+		t.Fatalf("PlaylistCount = %d, want 1", stats.PlaylistCount)
+	}
+}
+
+func TestReadRefreshResponseFromReaderRejectsInvalidJSON(t *testing.T) {
+	if _, err := readRefreshResponseFromReader(strings.NewReader("not json")); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// validateDatabase runs database.HealthCheck against db and reports the
+// result either as a structured JSON document (format == "json", for
+// CI/monitoring to consume) or as the traditional human-readable report
+// (any other value, including the empty string).
+func validateDatabase(db *database.DatabaseManager, format string) (*database.HealthReport, error) {
+	report, err := db.HealthCheck(database.NewSearchManager(db))
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println(string(data))
+		return report, nil
+	}
+	printHealthReport(report)
+	return report, nil
+}
+
+// printHealthReport renders report the way validateDatabase always has:
+// one emoji-decorated line per check.
+func printHealthReport(report *database.HealthReport) {
+	fmt.Printf("✅ tracks: %d\n", report.Stats.TrackCount)
+	fmt.Printf("✅ playlists: %d\n", report.Stats.PlaylistCount)
+	fmt.Printf("✅ radio stations: %d\n", report.Stats.StationCount)
+	if report.LastRefreshSkipped > 0 {
+		fmt.Printf("⚠️  last refresh skipped %d track(s)\n", report.LastRefreshSkipped)
+	}
+	if report.FTSOutOfSync {
+		fmt.Println("⚠️  tracks_fts out of sync with tracks, rebuilding")
+	} else {
+		fmt.Println("✅ tracks_fts in sync")
+	}
+	if report.OrphanedPlaylistTracks > 0 {
+		fmt.Printf("⚠️  %d orphaned playlist_tracks association(s)\n", report.OrphanedPlaylistTracks)
+	} else {
+		fmt.Println("✅ no orphaned playlist_tracks associations")
+	}
+	for _, s := range report.SampleSearches {
+		fmt.Printf("✅ sample search %q: %d result(s)\n", s.Query, s.Results)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// captureStdout runs fn with os.Stdout redirected to a pipe and returns
+// everything it wrote.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+	fn()
+	w.Close()
+	os.Stdout = old
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read captured stdout: %v", err)
+	}
+	return string(data)
+}
+
+func TestValidateDatabaseJSONFormat(t *testing.T) {
+	db, err := database.NewDatabaseManager("file:itunes_migrate_validate_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{
+			{PersistentID: "p1", Name: "Song One", Artist: "Artist"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	var report *database.HealthReport
+	output := captureStdout(t, func() {
+		report, err = validateDatabase(db, "json")
+	})
+	if err != nil {
+		t.Fatalf("validateDatabase: %v", err)
+	}
+	if report == nil || report.Stats.TrackCount != 1 {
+		t.Fatalf("got report %+v, want a TrackCount of 1", report)
+	}
+
+	var parsed database.HealthReport
+	if err := json.Unmarshal(bytes.TrimSpace([]byte(output)), &parsed); err != nil {
+		t.Fatalf("parse -format json output: %v\noutput: %s", err, output)
+	}
+	if parsed.Stats == nil || parsed.Stats.TrackCount != 1 {
+		t.Fatalf("got parsed stats %+v, want TrackCount 1", parsed.Stats)
+	}
+	if parsed.FTSOutOfSync {
+		t.Fatal("got FTSOutOfSync true right after a populate, want false")
+	}
+	if len(parsed.SampleSearches) == 0 {
+		t.Fatal("got no sample_searches in JSON output, want at least one")
+	}
+}
+
+func TestValidateDatabaseTextFormatIsHumanReadable(t *testing.T) {
+	db, err := database.NewDatabaseManager("file:itunes_migrate_validate_text_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	defer db.Close()
+
+	output := captureStdout(t, func() {
+		_, err = validateDatabase(db, "text")
+	})
+	if err != nil {
+		t.Fatalf("validateDatabase: %v", err)
+	}
+	if !bytes.Contains([]byte(output), []byte("tracks: 0")) {
+		t.Fatalf("got text output %q, want it to mention tracks: 0", output)
+	}
+}
@@ -0,0 +1,36 @@
+package database
+
+import "testing"
+
+// TestSearchTracksArtistPrefixIsAnchored confirms ArtistPrefix matches only
+// names starting with the given value, distinguishing it from Artist's
+// anywhere-in-the-name substring match: "The Beatles" matches prefix "The"
+// but not "Beat", even though "Beat" is a substring of the name.
+func TestSearchTracksArtistPrefixIsAnchored(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "Let It Be", Artist: "The Beatles"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	sm := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+
+	tracks, err := sm.SearchTracks("", &SearchFilters{ArtistPrefix: "The"})
+	if err != nil {
+		t.Fatalf("SearchTracks(ArtistPrefix=%q): %v", "The", err)
+	}
+	if len(tracks) != 1 {
+		t.Fatalf("got %d tracks for prefix %q, want 1", len(tracks), "The")
+	}
+
+	tracks, err = sm.SearchTracks("", &SearchFilters{ArtistPrefix: "Beat"})
+	if err != nil {
+		t.Fatalf("SearchTracks(ArtistPrefix=%q): %v", "Beat", err)
+	}
+	if len(tracks) != 0 {
+		t.Fatalf("got %d tracks for prefix %q, want 0 (Beat is a substring, not a prefix, of The Beatles)", len(tracks), "Beat")
+	}
+}
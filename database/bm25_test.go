@@ -0,0 +1,65 @@
+package database
+
+import "testing"
+
+// TestSearchTracksBM25RanksShorterExactMatchHigher seeds two tracks that
+// both match the same query term, but where only bm25's length
+// normalization (not the plain ranking/name tie-break) would put the
+// shorter, more focused match first, to confirm UseBM25 actually changes
+// ordering rather than just being plumbed through and ignored.
+func TestSearchTracksBM25RanksShorterExactMatchHigher(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "Love", Artist: "Artist One"},
+			{PersistentID: "p2", Name: "Endless Love Songs From The Heart And Soul Forever", Artist: "Artist Two"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	sm := NewSearchManager(db)
+
+	withBM25, err := sm.SearchTracks("love", nil)
+	if err != nil {
+		t.Fatalf("SearchTracks (bm25 default on): %v", err)
+	}
+	if len(withBM25) != 2 {
+		t.Fatalf("got %d results, want 2", len(withBM25))
+	}
+	if withBM25[0].PersistentID != "p1" {
+		t.Fatalf("got first result %q, want the short exact match (p1) ranked first by bm25", withBM25[0].PersistentID)
+	}
+	if sm.LastMetrics().Method != "fts" {
+		t.Fatalf("got method %q, want \"fts\" for a default text query", sm.LastMetrics().Method)
+	}
+
+	disabled := false
+	withoutBM25, err := sm.SearchTracks("love", &SearchFilters{UseBM25: &disabled})
+	if err != nil {
+		t.Fatalf("SearchTracks (bm25 off): %v", err)
+	}
+	if len(withoutBM25) != 2 {
+		t.Fatalf("got %d results, want 2", len(withoutBM25))
+	}
+	if withoutBM25[0].PersistentID != "p2" {
+		t.Fatalf("got first result %q, want the plain ranking/name tie-break order (p2 sorts before p1 alphabetically)", withoutBM25[0].PersistentID)
+	}
+	if sm.LastMetrics().Method != "like" {
+		t.Fatalf("got method %q, want \"like\" with bm25 explicitly disabled", sm.LastMetrics().Method)
+	}
+}
+
+func TestBuildFTSMatchQuery(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"love", "love*"},
+		{"  foo   bar  ", "foo* bar*"},
+		{"rock & roll", "rock* roll*"},
+		{"***", ""},
+		{"", ""},
+	}
+	for _, c := range cases {
+		if got := buildFTSMatchQuery(c.in); got != c.want {
+			t.Errorf("buildFTSMatchQuery(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
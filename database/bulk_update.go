@@ -0,0 +1,109 @@
+package database
+
+import "fmt"
+
+// BulkUpdateResult is BulkSetStarred/BulkSetRating's change set.
+type BulkUpdateResult struct {
+	Updated int `json:"updated"`
+}
+
+// BulkSetStarred sets the starred flag on every track matching filters.
+// When dryRun is true, the update is rolled back instead of committed, so
+// callers can preview how many tracks it would touch.
+func (m *DatabaseManager) BulkSetStarred(filters *SearchFilters, starred bool, dryRun bool) (*BulkUpdateResult, error) {
+	return m.bulkUpdateTracks(filters, "starred", starred, dryRun)
+}
+
+// BulkSetRating sets the rating on every track matching filters. When
+// dryRun is true, the update is rolled back instead of committed, so
+// callers can preview how many tracks it would touch.
+func (m *DatabaseManager) BulkSetRating(filters *SearchFilters, rating int, dryRun bool) (*BulkUpdateResult, error) {
+	return m.bulkUpdateTracks(filters, "rating", rating, dryRun)
+}
+
+// bulkUpdateTracks sets column = value on every track matching filters,
+// inside a transaction so dryRun can preview the change by rolling it back
+// instead of committing. column is always one of the literal strings
+// "starred"/"rating" from BulkSetStarred/BulkSetRating above, never
+// caller-supplied, so building the SET clause by concatenation is safe.
+func (m *DatabaseManager) bulkUpdateTracks(filters *SearchFilters, column string, value interface{}, dryRun bool) (*BulkUpdateResult, error) {
+	where, args := bulkFilterWhere(filters)
+
+	var result *BulkUpdateResult
+	err := m.enqueueWrite(func() error {
+		tx, err := m.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		res, err := tx.Exec(`
+			UPDATE tracks SET `+column+` = ?
+			WHERE id IN (
+				SELECT t.id FROM tracks t
+				LEFT JOIN artists ar ON ar.id = t.artist_id
+				LEFT JOIN albums al ON al.id = t.album_id
+				LEFT JOIN genres g ON g.id = t.genre_id
+				WHERE `+where+`
+			)`, append([]interface{}{value}, args...)...)
+		if err != nil {
+			return fmt.Errorf("database: bulk set %s: %w", column, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		result = &BulkUpdateResult{Updated: int(n)}
+
+		if dryRun {
+			return nil
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// bulkFilterWhere builds a WHERE clause (and its args) from filters, for
+// use against a query joining tracks/artists/albums/genres as t/ar/al/g.
+// A nil or all-zero filters matches every track.
+func bulkFilterWhere(filters *SearchFilters) (string, []interface{}) {
+	clause := "1 = 1"
+	var args []interface{}
+	if filters == nil {
+		return clause, args
+	}
+	if filters.Artist != "" {
+		clause += ` AND ar.name LIKE '%' || ? || '%'`
+		args = append(args, filters.Artist)
+	}
+	if filters.Album != "" {
+		clause += ` AND al.name LIKE '%' || ? || '%'`
+		args = append(args, filters.Album)
+	}
+	if filters.Genre != "" {
+		clause += ` AND g.name = ?`
+		args = append(args, filters.Genre)
+	}
+	if filters.Starred != nil {
+		clause += ` AND t.starred = ?`
+		args = append(args, *filters.Starred)
+	}
+	if filters.Disliked != nil {
+		clause += ` AND t.disliked = ?`
+		args = append(args, *filters.Disliked)
+	}
+	if filters.MinRating > 0 {
+		clause += ` AND t.rating >= ?`
+		args = append(args, filters.MinRating)
+	}
+	if filters.StreamingOnly != nil && *filters.StreamingOnly {
+		clause += ` AND t.is_streaming = 1`
+	}
+	if filters.LocalOnly != nil && *filters.LocalOnly {
+		clause += ` AND t.is_streaming = 0`
+	}
+	return clause, args
+}
@@ -0,0 +1,69 @@
+package database
+
+import "testing"
+
+func TestBulkSetStarredUpdatesOnlyMatchingTracks(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "One", Artist: "Artist A"},
+			{PersistentID: "p2", Name: "Two", Artist: "Artist A"},
+			{PersistentID: "p3", Name: "Three", Artist: "Artist B"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	result, err := db.BulkSetStarred(&SearchFilters{Artist: "Artist A"}, true, false)
+	if err != nil {
+		t.Fatalf("BulkSetStarred: %v", err)
+	}
+	if result.Updated != 2 {
+		t.Fatalf("Updated = %d, want 2", result.Updated)
+	}
+
+	for _, id := range []string{"p1", "p2"} {
+		tr, err := db.GetTrackByPersistentID(id)
+		if err != nil {
+			t.Fatalf("GetTrackByPersistentID(%q): %v", id, err)
+		}
+		if !tr.Starred {
+			t.Fatalf("track %q: Starred = false, want true", id)
+		}
+	}
+	p3, err := db.GetTrackByPersistentID("p3")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID(p3): %v", err)
+	}
+	if p3.Starred {
+		t.Fatal("track p3 should not have been starred")
+	}
+}
+
+func TestBulkSetRatingDryRunLeavesRowsUnchanged(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "One", Genre: "Jazz"},
+			{PersistentID: "p2", Name: "Two", Genre: "Rock"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	result, err := db.BulkSetRating(&SearchFilters{Genre: "Jazz"}, 80, true)
+	if err != nil {
+		t.Fatalf("BulkSetRating (dry-run): %v", err)
+	}
+	if result.Updated != 1 {
+		t.Fatalf("Updated = %d, want 1", result.Updated)
+	}
+
+	p1, err := db.GetTrackByPersistentID("p1")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID(p1): %v", err)
+	}
+	if p1.Rating == 80 {
+		t.Fatal("dry-run BulkSetRating committed its change; rating should be unchanged")
+	}
+}
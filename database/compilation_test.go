@@ -0,0 +1,56 @@
+package database
+
+import "testing"
+
+func TestCompilationTracksShareOneAlbum(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "c1", Name: "Track A", Artist: "Artist One", Album: "Now That's What I Call Music", AlbumArtist: "Various Artists", Compilation: true},
+			{PersistentID: "c2", Name: "Track B", Artist: "Artist Two", Album: "Now That's What I Call Music", AlbumArtist: "Various Artists", Compilation: true},
+			{PersistentID: "c3", Name: "Track C", Artist: "Artist Three", Album: "Now That's What I Call Music", AlbumArtist: "Various Artists", Compilation: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	var albumCount int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM albums WHERE name = ?`, "Now That's What I Call Music").Scan(&albumCount); err != nil {
+		t.Fatalf("count albums: %v", err)
+	}
+	if albumCount != 1 {
+		t.Fatalf("got %d album rows for the compilation, want 1", albumCount)
+	}
+
+	var compilation bool
+	if err := db.DB.QueryRow(`SELECT compilation FROM albums WHERE name = ?`, "Now That's What I Call Music").Scan(&compilation); err != nil {
+		t.Fatalf("read compilation flag: %v", err)
+	}
+	if !compilation {
+		t.Fatal("expected the album's compilation flag to be set")
+	}
+}
+
+func TestNonCompilationAlbumsStillSplitByArtist(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "n1", Name: "Song", Artist: "Band A", Album: "Greatest Hits"},
+			{PersistentID: "n2", Name: "Song", Artist: "Band B", Album: "Greatest Hits"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	var albumCount int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM albums WHERE name = ?`, "Greatest Hits").Scan(&albumCount); err != nil {
+		t.Fatalf("count albums: %v", err)
+	}
+	if albumCount != 2 {
+		t.Fatalf("got %d album rows, want 2 (one per artist)", albumCount)
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import "testing"
+
+func TestImportRadioStationsDedupe(t *testing.T) {
+	db := newTestDB(t)
+
+	variants := []RadioStation{
+		{Name: "Jazz24", URL: "https://jazz24.example.com/stream"},
+		{Name: "Jazz24", URL: "HTTPS://jazz24.example.com/stream"},
+		{Name: "Jazz24", URL: "https://jazz24.example.com/stream?app=music"},
+	}
+
+	result, err := db.ImportRadioStations(variants, ImportOptions{Dedupe: true})
+	if err != nil {
+		t.Fatalf("ImportRadioStations: %v", err)
+	}
+	if result.Imported != 1 || result.Duplicates != 2 {
+		t.Fatalf("got imported=%d duplicates=%d, want imported=1 duplicates=2", result.Imported, result.Duplicates)
+	}
+
+	stations, err := db.ListRadioStations(nil)
+	if err != nil {
+		t.Fatalf("ListRadioStations: %v", err)
+	}
+	if len(stations) != 1 {
+		t.Fatalf("got %d stored stations, want 1", len(stations))
+	}
+}
+
+func TestImportRadioStationsAllDuplicatesErrors(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.ImportRadioStations([]RadioStation{
+		{Name: "Jazz24", URL: "https://jazz24.example.com/stream"},
+	}, ImportOptions{}); err != nil {
+		t.Fatalf("ImportRadioStations (seed): %v", err)
+	}
+
+	result, err := db.ImportRadioStations([]RadioStation{
+		{Name: "Jazz24 mirror", URL: "https://jazz24.example.com/stream"},
+	}, ImportOptions{Dedupe: true})
+	if err == nil {
+		t.Fatal("ImportRadioStations: want error when zero stations imported, got nil")
+	}
+	if result == nil || result.Imported != 0 || result.Duplicates != 1 {
+		t.Fatalf("got %+v, want imported=0 duplicates=1", result)
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import "testing"
+
+func TestSetTrackDislikedAndFilter(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "Keeper", Artist: "Artist"},
+			{PersistentID: "p2", Name: "Skip Me", Artist: "Artist"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	if err := db.SetTrackDisliked("p2", true); err != nil {
+		t.Fatalf("SetTrackDisliked: %v", err)
+	}
+
+	track, err := db.GetTrackByPersistentID("p2")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID: %v", err)
+	}
+	if !track.Disliked {
+		t.Fatal("expected p2.Disliked = true")
+	}
+
+	sm := NewSearchManager(db)
+	disliked := true
+	results, err := sm.SearchTracks("", &SearchFilters{Disliked: &disliked})
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(results) != 1 || results[0].PersistentID != "p2" {
+		t.Fatalf("got %+v, want only p2", results)
+	}
+
+	notDisliked := false
+	results, err = sm.SearchTracks("", &SearchFilters{Disliked: &notDisliked})
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(results) != 1 || results[0].PersistentID != "p1" {
+		t.Fatalf("got %+v, want only p1", results)
+	}
+}
+
+func TestSetTrackDislikedUnknownID(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.SetTrackDisliked("missing", true); err == nil {
+		t.Fatal("expected an error for an unknown persistent ID")
+	}
+}
@@ -0,0 +1,36 @@
+package database
+
+import "testing"
+
+func TestSearchFiltersDownloadedOnly(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "Local Copy", Artist: "Artist", Downloaded: true},
+			{PersistentID: "p2", Name: "Cloud Only", Artist: "Artist", Downloaded: false},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	sm := NewSearchManager(db)
+
+	downloaded := true
+	results, err := sm.SearchTracks("", &SearchFilters{DownloadedOnly: &downloaded})
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(results) != 1 || results[0].PersistentID != "p1" {
+		t.Fatalf("got %+v, want only p1", results)
+	}
+
+	cloudOnly := false
+	results, err = sm.SearchTracks("", &SearchFilters{DownloadedOnly: &cloudOnly})
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(results) != 1 || results[0].PersistentID != "p2" {
+		t.Fatalf("got %+v, want only p2", results)
+	}
+}
@@ -0,0 +1,142 @@
+package database
+
+import "testing"
+
+func TestSyncPlaylistDryRunLeavesRowsUnchanged(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "One"},
+			{PersistentID: "p2", Name: "Two"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	p1, err := db.GetTrackByPersistentID("p1")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID: %v", err)
+	}
+	p2, err := db.GetTrackByPersistentID("p2")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID: %v", err)
+	}
+
+	var playlistID int64
+	if err := db.DB.QueryRow(`INSERT INTO playlists (persistent_id, name) VALUES ('pl1', 'Favorites') RETURNING id`).Scan(&playlistID); err != nil {
+		t.Fatalf("insert playlist: %v", err)
+	}
+	if _, err := db.SyncPlaylist(playlistID, []int64{p1.ID}, false); err != nil {
+		t.Fatalf("seed SyncPlaylist: %v", err)
+	}
+	assertTrackCount(t, db, playlistID, 1)
+
+	result, err := db.SyncPlaylist(playlistID, []int64{p2.ID}, true)
+	if err != nil {
+		t.Fatalf("SyncPlaylist (dry-run): %v", err)
+	}
+	if len(result.Added) != 1 || result.Added[0] != p2.ID {
+		t.Fatalf("got Added=%v, want [%d]", result.Added, p2.ID)
+	}
+	if len(result.Removed) != 1 || result.Removed[0] != p1.ID {
+		t.Fatalf("got Removed=%v, want [%d]", result.Removed, p1.ID)
+	}
+	if result.FinalCount != 1 {
+		t.Fatalf("FinalCount = %d, want 1", result.FinalCount)
+	}
+
+	// The dry-run must not have committed anything: the playlist should
+	// still contain p1, not p2.
+	assertTrackCount(t, db, playlistID, 1)
+	var stillP1 int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM playlist_tracks WHERE playlist_id = ? AND track_id = ?`, playlistID, p1.ID).Scan(&stillP1); err != nil {
+		t.Fatalf("query playlist_tracks: %v", err)
+	}
+	if stillP1 != 1 {
+		t.Fatal("dry-run SyncPlaylist committed its change; p1 should still be the sole member")
+	}
+}
+
+func TestDeleteRadioStationDryRunLeavesRowUnchanged(t *testing.T) {
+	db := newTestDB(t)
+
+	res, err := db.DB.Exec(`INSERT INTO radio_stations (name, url) VALUES ('Station', 'https://example.com/stream')`)
+	if err != nil {
+		t.Fatalf("insert station: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("LastInsertId: %v", err)
+	}
+
+	result, err := db.DeleteRadioStation(id, true)
+	if err != nil {
+		t.Fatalf("DeleteRadioStation (dry-run): %v", err)
+	}
+	if !result.Deleted {
+		t.Fatal("expected Deleted=true to report the row that would be removed")
+	}
+
+	var count int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM radio_stations WHERE id = ?`, id).Scan(&count); err != nil {
+		t.Fatalf("query radio_stations: %v", err)
+	}
+	if count != 1 {
+		t.Fatal("dry-run DeleteRadioStation committed its change; station should still exist")
+	}
+
+	if _, err := db.DeleteRadioStation(id, false); err != nil {
+		t.Fatalf("DeleteRadioStation: %v", err)
+	}
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM radio_stations WHERE id = ?`, id).Scan(&count); err != nil {
+		t.Fatalf("query radio_stations: %v", err)
+	}
+	if count != 0 {
+		t.Fatal("expected the station to be gone after a non-dry-run delete")
+	}
+}
+
+func TestDeleteInactiveStationsRemovesOnlyInactive(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.DB.Exec(`INSERT INTO radio_stations (name, url, is_active) VALUES ('Active', 'https://example.com/active', 1)`); err != nil {
+		t.Fatalf("insert active station: %v", err)
+	}
+	if _, err := db.DB.Exec(`INSERT INTO radio_stations (name, url, is_active) VALUES ('Dead 1', 'https://example.com/dead1', 0)`); err != nil {
+		t.Fatalf("insert inactive station: %v", err)
+	}
+	if _, err := db.DB.Exec(`INSERT INTO radio_stations (name, url, is_active) VALUES ('Dead 2', 'https://example.com/dead2', 0)`); err != nil {
+		t.Fatalf("insert inactive station: %v", err)
+	}
+
+	n, err := db.DeleteInactiveStations(true)
+	if err != nil {
+		t.Fatalf("DeleteInactiveStations (dry-run): %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("got %d, want 2", n)
+	}
+	var total int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM radio_stations`).Scan(&total); err != nil {
+		t.Fatalf("query radio_stations: %v", err)
+	}
+	if total != 3 {
+		t.Fatal("dry-run DeleteInactiveStations committed its change; all 3 stations should still exist")
+	}
+
+	n, err = db.DeleteInactiveStations(false)
+	if err != nil {
+		t.Fatalf("DeleteInactiveStations: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("got %d, want 2", n)
+	}
+	stations, err := db.ListRadioStations(nil)
+	if err != nil {
+		t.Fatalf("ListRadioStations: %v", err)
+	}
+	if len(stations) != 1 || stations[0].Name != "Active" {
+		t.Fatalf("got %+v, want only the active station", stations)
+	}
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// TrackEdit is UpdateTrackFields' input: only non-nil fields are changed,
+// so callers can patch a single field (e.g. just Genre) without touching
+// the rest of the row.
+type TrackEdit struct {
+	Name   *string
+	Artist *string
+	Album  *string
+	Genre  *string
+}
+
+// UpdateTrackFields applies edit's non-nil fields to the track identified
+// by persistentID, re-resolving artist/album/genre foreign keys (creating
+// new rows as needed) and pruning any artist/album/genre row left with no
+// tracks referencing it, so e.g. retagging a track's genre doesn't leave an
+// orphaned genre row behind. Returns ErrNoTracksFound if no track matches
+// persistentID.
+func (m *DatabaseManager) UpdateTrackFields(persistentID string, edit TrackEdit) error {
+	return m.enqueueWrite(func() error {
+		var trackID, oldArtistID, oldAlbumID, oldGenreID int64
+		err := m.DB.QueryRow(`SELECT id, artist_id, album_id, genre_id FROM tracks WHERE persistent_id = ?`, persistentID).
+			Scan(&trackID, &oldArtistID, &oldAlbumID, &oldGenreID)
+		if err == sql.ErrNoRows {
+			return ErrNoTracksFound
+		}
+		if err != nil {
+			return fmt.Errorf("database: update track fields: %w", err)
+		}
+
+		newArtistID, newAlbumID, newGenreID := oldArtistID, oldAlbumID, oldGenreID
+
+		if edit.Artist != nil {
+			newArtistID, err = m.getOrCreateNamed("artists", *edit.Artist)
+			if err != nil {
+				return err
+			}
+		}
+		switch {
+		case edit.Album != nil:
+			newAlbumID, err = m.getOrCreateAlbum(*edit.Album, newArtistID, false)
+			if err != nil {
+				return err
+			}
+		case edit.Artist != nil:
+			// The artist changed but the album name didn't: re-point the
+			// existing album name at the new artist rather than leaving the
+			// track on an album still keyed to the old one.
+			var albumName string
+			if err := m.DB.QueryRow(`SELECT name FROM albums WHERE id = ?`, oldAlbumID).Scan(&albumName); err != nil {
+				return err
+			}
+			newAlbumID, err = m.getOrCreateAlbum(albumName, newArtistID, false)
+			if err != nil {
+				return err
+			}
+		}
+		if edit.Genre != nil {
+			newGenreID, err = m.getOrCreateNamed("genres", *edit.Genre)
+			if err != nil {
+				return err
+			}
+		}
+
+		query := `UPDATE tracks SET artist_id = ?, album_id = ?, genre_id = ?`
+		args := []interface{}{newArtistID, newAlbumID, newGenreID}
+		if edit.Name != nil {
+			query += `, name = ?`
+			args = append(args, *edit.Name)
+		}
+		query += ` WHERE id = ?`
+		args = append(args, trackID)
+		if _, err := m.DB.Exec(query, args...); err != nil {
+			return fmt.Errorf("database: update track fields: %w", err)
+		}
+
+		if newArtistID != oldArtistID {
+			if err := m.pruneIfUnused("artists", "artist_id", oldArtistID); err != nil {
+				return err
+			}
+		}
+		if newAlbumID != oldAlbumID {
+			if err := m.pruneIfUnused("albums", "album_id", oldAlbumID); err != nil {
+				return err
+			}
+		}
+		if newGenreID != oldGenreID {
+			if err := m.pruneIfUnused("genres", "genre_id", oldGenreID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// pruneIfUnused deletes row id from table if no tracks reference it through
+// fkColumn, so editing a track's metadata doesn't leave an orphaned
+// artist/album/genre row behind.
+func (m *DatabaseManager) pruneIfUnused(table, fkColumn string, id int64) error {
+	var count int
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM tracks WHERE `+fkColumn+` = ?`, id).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err := m.DB.Exec(`DELETE FROM `+table+` WHERE id = ?`, id)
+	return err
+}
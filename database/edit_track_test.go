@@ -0,0 +1,131 @@
+package database
+
+import "testing"
+
+func strPtr(s string) *string { return &s }
+
+func seedEditableTrack(t *testing.T, db *DatabaseManager) string {
+	t.Helper()
+	const persistentID = "track-edit-1"
+	if _, err := db.insertOrUpdateRefreshTrack(RefreshTrack{
+		PersistentID: persistentID,
+		Name:         "Original Name",
+		Artist:       "Original Artist",
+		Album:        "Original Album",
+		Genre:        "Original Genre",
+	}); err != nil {
+		t.Fatalf("insertOrUpdateRefreshTrack: %v", err)
+	}
+	return persistentID
+}
+
+func getTrackRow(t *testing.T, db *DatabaseManager, persistentID string) (name, artist, album, genre string) {
+	t.Helper()
+	err := db.DB.QueryRow(`
+		SELECT tracks.name, artists.name, albums.name, genres.name
+		FROM tracks
+		JOIN artists ON artists.id = tracks.artist_id
+		JOIN albums ON albums.id = tracks.album_id
+		JOIN genres ON genres.id = tracks.genre_id
+		WHERE tracks.persistent_id = ?`, persistentID).Scan(&name, &artist, &album, &genre)
+	if err != nil {
+		t.Fatalf("query track row: %v", err)
+	}
+	return
+}
+
+func TestUpdateTrackFieldsUpdatesEachFieldIndependently(t *testing.T) {
+	db := newTestDB(t)
+	persistentID := seedEditableTrack(t, db)
+
+	if err := db.UpdateTrackFields(persistentID, TrackEdit{Name: strPtr("New Name")}); err != nil {
+		t.Fatalf("UpdateTrackFields (name): %v", err)
+	}
+	name, artist, album, genre := getTrackRow(t, db, persistentID)
+	if name != "New Name" || artist != "Original Artist" || album != "Original Album" || genre != "Original Genre" {
+		t.Fatalf("got name=%q artist=%q album=%q genre=%q, want only name changed", name, artist, album, genre)
+	}
+
+	if err := db.UpdateTrackFields(persistentID, TrackEdit{Genre: strPtr("New Genre")}); err != nil {
+		t.Fatalf("UpdateTrackFields (genre): %v", err)
+	}
+	_, _, _, genre = getTrackRow(t, db, persistentID)
+	if genre != "New Genre" {
+		t.Fatalf("got genre=%q, want %q", genre, "New Genre")
+	}
+
+	if err := db.UpdateTrackFields(persistentID, TrackEdit{Album: strPtr("New Album")}); err != nil {
+		t.Fatalf("UpdateTrackFields (album): %v", err)
+	}
+	_, _, album, _ = getTrackRow(t, db, persistentID)
+	if album != "New Album" {
+		t.Fatalf("got album=%q, want %q", album, "New Album")
+	}
+
+	if err := db.UpdateTrackFields(persistentID, TrackEdit{Artist: strPtr("New Artist")}); err != nil {
+		t.Fatalf("UpdateTrackFields (artist): %v", err)
+	}
+	_, artist, album, _ = getTrackRow(t, db, persistentID)
+	if artist != "New Artist" || album != "New Album" {
+		t.Fatalf("got artist=%q album=%q, want artist changed and album kept under the new artist", artist, album)
+	}
+}
+
+func TestUpdateTrackFieldsPrunesUnusedOldRows(t *testing.T) {
+	db := newTestDB(t)
+	persistentID := seedEditableTrack(t, db)
+
+	if err := db.UpdateTrackFields(persistentID, TrackEdit{
+		Artist: strPtr("New Artist"), Album: strPtr("New Album"), Genre: strPtr("New Genre"),
+	}); err != nil {
+		t.Fatalf("UpdateTrackFields: %v", err)
+	}
+
+	for _, q := range []struct{ table, name string }{
+		{"artists", "Original Artist"},
+		{"albums", "Original Album"},
+		{"genres", "Original Genre"},
+	} {
+		var count int
+		if err := db.DB.QueryRow(`SELECT COUNT(*) FROM `+q.table+` WHERE name = ?`, q.name).Scan(&count); err != nil {
+			t.Fatalf("count %s: %v", q.table, err)
+		}
+		if count != 0 {
+			t.Fatalf("%s %q still present after becoming unused", q.table, q.name)
+		}
+	}
+}
+
+func TestUpdateTrackFieldsKeepsStillReferencedRows(t *testing.T) {
+	db := newTestDB(t)
+	persistentID := seedEditableTrack(t, db)
+	if _, err := db.insertOrUpdateRefreshTrack(RefreshTrack{
+		PersistentID: "track-edit-2",
+		Name:         "Other Track",
+		Artist:       "Original Artist",
+		Album:        "Other Album",
+		Genre:        "Original Genre",
+	}); err != nil {
+		t.Fatalf("insertOrUpdateRefreshTrack: %v", err)
+	}
+
+	if err := db.UpdateTrackFields(persistentID, TrackEdit{Artist: strPtr("New Artist")}); err != nil {
+		t.Fatalf("UpdateTrackFields: %v", err)
+	}
+
+	var count int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM artists WHERE name = ?`, "Original Artist").Scan(&count); err != nil {
+		t.Fatalf("count artists: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("got %d artists named Original Artist, want 1 (still referenced by track-edit-2)", count)
+	}
+}
+
+func TestUpdateTrackFieldsNoMatchingTrack(t *testing.T) {
+	db := newTestDB(t)
+	err := db.UpdateTrackFields("does-not-exist", TrackEdit{Name: strPtr("New Name")})
+	if err != ErrNoTracksFound {
+		t.Fatalf("got %v, want ErrNoTracksFound", err)
+	}
+}
@@ -0,0 +1,25 @@
+package database
+
+import "errors"
+
+// ErrNoTracksFound is returned when a query that expects at least one
+// matching track comes back empty.
+var ErrNoTracksFound = errors.New("database: no tracks found")
+
+// ErrPlaylistNotFound indicates a playlist lookup by ID/persistent ID did
+// not match any row.
+var ErrPlaylistNotFound = errors.New("database: playlist not found")
+
+// ErrStationNotFound indicates a radio station lookup did not match any
+// row.
+var ErrStationNotFound = errors.New("database: station not found")
+
+// ErrSmartPlaylist is returned by playlist write-back operations
+// (SetPlaylistTracksByPersistentID, MovePlaylistTrackByPersistentID) when
+// the target playlist is a smart playlist, whose membership/order is
+// computed by Apple Music itself and isn't meant to be edited directly.
+var ErrSmartPlaylist = errors.New("database: playlist is a smart playlist")
+
+// ErrPersistentIDConflict is returned by RemapTrackPersistentID when the
+// requested new persistent ID already belongs to a different track.
+var ErrPersistentIDConflict = errors.New("database: persistent id already in use by another track")
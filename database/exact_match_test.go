@@ -0,0 +1,40 @@
+package database
+
+import "testing"
+
+func TestSearchTracksExactMatchIsAccentAndCaseSensitive(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "café society", Artist: "Artist"},
+			{PersistentID: "p2", Name: "cafe society", Artist: "Artist"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	sm := NewSearchManager(db)
+
+	loose, err := sm.SearchTracks("cafe", nil)
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(loose) != 2 {
+		t.Fatalf("got %+v, want both tracks under default matching, which folds accents", loose)
+	}
+
+	exact, err := sm.SearchTracks("café", &SearchFilters{ExactMatch: true})
+	if err != nil {
+		t.Fatalf("SearchTracks (exact): %v", err)
+	}
+	if len(exact) != 1 || exact[0].PersistentID != "p1" {
+		t.Fatalf("got %+v, want only the accented track under exact matching", exact)
+	}
+
+	caseSensitive, err := sm.SearchTracks("Cafe", &SearchFilters{ExactMatch: true})
+	if err != nil {
+		t.Fatalf("SearchTracks (exact, wrong case): %v", err)
+	}
+	if len(caseSensitive) != 0 {
+		t.Fatalf("got %d results, want 0 since exact matching is case-sensitive", len(caseSensitive))
+	}
+}
@@ -0,0 +1,37 @@
+package database
+
+import "testing"
+
+// TestSearchTracksExactNameMatchesOnlyExactTitle confirms ExactName matches
+// the track title exactly (case-insensitively), distinguishing it from the
+// default substring match: "So What" matches query "so what" but not the
+// substring query "What", which would otherwise match via LIKE/FTS.
+func TestSearchTracksExactNameMatchesOnlyExactTitle(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "So What", Artist: "Miles Davis"},
+			{PersistentID: "p2", Name: "What", Artist: "Miles Davis"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	sm := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+
+	tracks, err := sm.SearchTracks("so what", &SearchFilters{ExactName: true})
+	if err != nil {
+		t.Fatalf("SearchTracks(ExactName, %q): %v", "so what", err)
+	}
+	if len(tracks) != 1 || tracks[0].Name != "So What" {
+		t.Fatalf("got %+v, want exactly [So What]", tracks)
+	}
+
+	tracks, err = sm.SearchTracks("What", &SearchFilters{ExactName: true})
+	if err != nil {
+		t.Fatalf("SearchTracks(ExactName, %q): %v", "What", err)
+	}
+	if len(tracks) != 1 || tracks[0].Name != "What" {
+		t.Fatalf("got %+v, want exactly [What] (not substring-matching So What)", tracks)
+	}
+}
@@ -0,0 +1,26 @@
+package database
+
+import "testing"
+
+func TestRadioStationExportRoundTrip(t *testing.T) {
+	original := RadioStation{
+		ID:          42,
+		Name:        "Jazz24",
+		URL:         "https://jazz24.example.com/stream",
+		Description: "All jazz, all the time",
+		Genre:       "Jazz",
+		GenreID:     7,
+		Homepage:    "https://jazz24.example.com",
+	}
+
+	roundTripped := original.ToExport().FromExport()
+
+	if roundTripped.Name != original.Name || roundTripped.URL != original.URL ||
+		roundTripped.Description != original.Description || roundTripped.Genre != original.Genre ||
+		roundTripped.Homepage != original.Homepage {
+		t.Fatalf("round trip changed content: got %+v, want content of %+v", roundTripped, original)
+	}
+	if roundTripped.ID != 0 || roundTripped.GenreID != 0 {
+		t.Fatalf("round trip should not carry internal IDs across, got ID=%d GenreID=%d", roundTripped.ID, roundTripped.GenreID)
+	}
+}
@@ -0,0 +1,48 @@
+package database
+
+import "fmt"
+
+// CheckFTSDrift compares the tracks table's row count against tracks_fts's,
+// returning true if they've diverged. The tracks_ai/tracks_ad/tracks_au
+// triggers in schema.go keep tracks_fts incrementally in sync on every
+// insert/update/delete, so normal operation (including a library refresh
+// or migration) should never drift and search stays queryable throughout.
+// This is a defensive check for a database that predates those triggers or
+// was touched outside this package (e.g. direct SQL edits).
+func (m *DatabaseManager) CheckFTSDrift() (bool, error) {
+	var trackCount, ftsCount int
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM tracks`).Scan(&trackCount); err != nil {
+		return false, fmt.Errorf("database: count tracks: %w", err)
+	}
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM tracks_fts`).Scan(&ftsCount); err != nil {
+		return false, fmt.Errorf("database: count tracks_fts: %w", err)
+	}
+	return trackCount != ftsCount, nil
+}
+
+// RebuildFTS fully repopulates tracks_fts from tracks. It's the fallback
+// for the rare case CheckFTSDrift reports a mismatch; the normal
+// insert/update/delete paths never need it.
+func (m *DatabaseManager) RebuildFTS() error {
+	return m.enqueueWrite(func() error {
+		tx, err := m.DB.Begin()
+		if err != nil {
+			return fmt.Errorf("database: rebuild FTS: %w", err)
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.Exec(`DELETE FROM tracks_fts`); err != nil {
+			return fmt.Errorf("database: rebuild FTS: clear: %w", err)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO tracks_fts(rowid, name, artist, album, genre)
+			SELECT t.id, t.name,
+				(SELECT name FROM artists WHERE id = t.artist_id),
+				(SELECT name FROM albums WHERE id = t.album_id),
+				(SELECT name FROM genres WHERE id = t.genre_id)
+			FROM tracks t`); err != nil {
+			return fmt.Errorf("database: rebuild FTS: repopulate: %w", err)
+		}
+		return tx.Commit()
+	})
+}
@@ -0,0 +1,72 @@
+package database
+
+import "testing"
+
+func TestFTSStaysInSyncDuringIncrementalPopulate(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "f1", Name: "So What", Artist: "Miles Davis", Album: "Kind of Blue"},
+			{PersistentID: "f2", Name: "Freddie Freeloader", Artist: "Miles Davis", Album: "Kind of Blue"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	drift, err := db.CheckFTSDrift()
+	if err != nil {
+		t.Fatalf("CheckFTSDrift: %v", err)
+	}
+	if drift {
+		t.Fatal("expected no FTS drift after an incremental populate; triggers should keep tracks_fts in sync")
+	}
+
+	var matches int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM tracks_fts WHERE tracks_fts MATCH 'Miles'`).Scan(&matches); err != nil {
+		t.Fatalf("query tracks_fts: %v", err)
+	}
+	if matches != 2 {
+		t.Fatalf("got %d FTS matches for Miles, want 2", matches)
+	}
+}
+
+func TestRebuildFTSRecoversFromDrift(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "f1", Name: "So What", Artist: "Miles Davis", Album: "Kind of Blue"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	// Simulate drift from something bypassing the triggers (e.g. a direct
+	// SQL edit outside this package).
+	if _, err := db.DB.Exec(`DELETE FROM tracks_fts`); err != nil {
+		t.Fatalf("simulate drift: %v", err)
+	}
+
+	drift, err := db.CheckFTSDrift()
+	if err != nil {
+		t.Fatalf("CheckFTSDrift: %v", err)
+	}
+	if !drift {
+		t.Fatal("expected drift after manually clearing tracks_fts")
+	}
+
+	if err := db.RebuildFTS(); err != nil {
+		t.Fatalf("RebuildFTS: %v", err)
+	}
+
+	drift, err = db.CheckFTSDrift()
+	if err != nil {
+		t.Fatalf("CheckFTSDrift: %v", err)
+	}
+	if drift {
+		t.Fatal("expected no drift after RebuildFTS")
+	}
+}
@@ -0,0 +1,93 @@
+package database
+
+import "fmt"
+
+// SampleSearch is one probe HealthCheck ran against the library to confirm
+// search actually returns results, not just that row counts look right.
+type SampleSearch struct {
+	Query   string `json:"query"`
+	Results int    `json:"results"`
+}
+
+// HealthReport is a structured summary of a database's sanity: row counts,
+// how many tracks the last refresh skipped, whether tracks_fts needed a
+// rebuild, orphaned playlist_tracks associations, and a couple of sample
+// searches run against the live data.
+type HealthReport struct {
+	Stats                  *Stats         `json:"stats"`
+	LastRefreshSkipped     int            `json:"last_refresh_skipped_tracks"`
+	FTSOutOfSync           bool           `json:"fts_out_of_sync"`
+	FTSRebuilt             bool           `json:"fts_rebuilt,omitempty"`
+	OrphanedPlaylistTracks int            `json:"orphaned_playlist_tracks"`
+	SampleSearches         []SampleSearch `json:"sample_searches,omitempty"`
+}
+
+// CountOrphanedPlaylistTracks returns the number of playlist_tracks rows
+// whose playlist_id or track_id no longer resolves to an existing row.
+// SQLite's REFERENCES clauses in the schema aren't enforced (foreign keys
+// aren't turned on), so nothing currently prevents this besides every
+// write path being careful; this is the sanity check for when one isn't.
+func (m *DatabaseManager) CountOrphanedPlaylistTracks() (int, error) {
+	var n int
+	err := m.DB.QueryRow(`
+		SELECT COUNT(*) FROM playlist_tracks pt
+		WHERE NOT EXISTS (SELECT 1 FROM playlists p WHERE p.id = pt.playlist_id)
+		   OR NOT EXISTS (SELECT 1 FROM tracks t WHERE t.id = pt.track_id)`).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("database: count orphaned playlist tracks: %w", err)
+	}
+	return n, nil
+}
+
+// HealthCheck runs the same sanity checks the itunes-migrate validate
+// command has always performed, gathered into a single structured result
+// so a caller can print it, serialize it as JSON, or inspect it
+// programmatically instead of scraping stdout. It rebuilds tracks_fts if
+// CheckFTSDrift finds it out of sync. sm is optional; when non-nil, a
+// couple of sample searches are run against the live data to confirm
+// search itself returns results, not just that the row counts look right.
+func (m *DatabaseManager) HealthCheck(sm *SearchManager) (*HealthReport, error) {
+	stats, err := m.GetStats()
+	if err != nil {
+		return nil, err
+	}
+	skipped, err := m.GetLastRefreshSkippedTracks()
+	if err != nil {
+		return nil, err
+	}
+	report := &HealthReport{Stats: stats, LastRefreshSkipped: skipped}
+
+	drift, err := m.CheckFTSDrift()
+	if err != nil {
+		return nil, err
+	}
+	report.FTSOutOfSync = drift
+	if drift {
+		if err := m.RebuildFTS(); err != nil {
+			return nil, err
+		}
+		report.FTSRebuilt = true
+	}
+
+	orphaned, err := m.CountOrphanedPlaylistTracks()
+	if err != nil {
+		return nil, err
+	}
+	report.OrphanedPlaylistTracks = orphaned
+
+	if sm != nil {
+		any, err := sm.SearchTracks("", nil)
+		if err != nil {
+			return nil, err
+		}
+		report.SampleSearches = append(report.SampleSearches, SampleSearch{Query: "", Results: len(any)})
+		if len(any) > 0 {
+			named, err := sm.SearchTracks(any[0].Name, nil)
+			if err != nil {
+				return nil, err
+			}
+			report.SampleSearches = append(report.SampleSearches, SampleSearch{Query: any[0].Name, Results: len(named)})
+		}
+	}
+	return report, nil
+}
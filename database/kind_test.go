@@ -0,0 +1,37 @@
+package database
+
+import "testing"
+
+func TestListTrackKindsAndSearchFiltersKind(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "Song One", Artist: "Artist", TrackKind: "MPEG audio file"},
+			{PersistentID: "p2", Name: "Song Two", Artist: "Artist", TrackKind: "MPEG audio file"},
+			{PersistentID: "p3", Name: "Stream", Artist: "Artist", TrackKind: "Internet audio stream"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	kinds, err := db.ListTrackKinds()
+	if err != nil {
+		t.Fatalf("ListTrackKinds: %v", err)
+	}
+	if len(kinds) != 2 {
+		t.Fatalf("got %d kinds, want 2", len(kinds))
+	}
+	if kinds[0].Kind != "MPEG audio file" || kinds[0].Count != 2 {
+		t.Fatalf("got %+v first, want MPEG audio file with count 2 (most common first)", kinds[0])
+	}
+
+	sm := NewSearchManager(db)
+	results, err := sm.SearchTracks("", &SearchFilters{Kind: "Internet audio stream"})
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(results) != 1 || results[0].PersistentID != "p3" {
+		t.Fatalf("got %+v, want only p3", results)
+	}
+}
@@ -0,0 +1,31 @@
+package database
+
+import "testing"
+
+func TestListPlaylistsExcludesSpecialByDefault(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "Favorites"},
+			{PersistentID: "pl2", Name: "Recently Added", SpecialKind: "recentlyAdded"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	userOnly, err := db.ListPlaylists(nil, false, 0, 0)
+	if err != nil {
+		t.Fatalf("ListPlaylists(includeSpecial=false): %v", err)
+	}
+	if userOnly.Total != 1 || len(userOnly.Playlists) != 1 || userOnly.Playlists[0].PersistentID != "pl1" {
+		t.Fatalf("got %+v, want only pl1", userOnly)
+	}
+
+	withSpecial, err := db.ListPlaylists(nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListPlaylists(includeSpecial=true): %v", err)
+	}
+	if withSpecial.Total != 2 || len(withSpecial.Playlists) != 2 {
+		t.Fatalf("got %+v, want both pl1 and pl2", withSpecial)
+	}
+}
@@ -0,0 +1,282 @@
+// Package database owns the SQLite-backed library cache: schema,
+// migrations, and the query methods used by the itunes package and the
+// migrate tool.
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chew-z/itunes.vim/pathutil"
+	_ "modernc.org/sqlite"
+)
+
+// DatabaseManager owns the SQLite connection and exposes the query/command
+// methods used by the rest of the application.
+type DatabaseManager struct {
+	DB   *sql.DB
+	Path string
+
+	// readOnly is set by NewDatabaseManagerReadOnly. It skips starting the
+	// write queue (there's nothing to serialize) and makes enqueueWrite
+	// fail fast instead of blocking forever trying to send on a queue that
+	// was never started.
+	readOnly bool
+
+	// writeQueue serializes every write through a single goroutine so
+	// concurrent callers in this process never contend for SQLite's one
+	// writer lock; see writequeue.go.
+	writeQueue chan writeJob
+}
+
+// ResolveDBPath determines the database path honoring, in order: an
+// explicit argument, the ITUNES_DB_PATH environment variable, the active
+// profile set via pathutil.SetActiveProfile (itunes profiles use), then
+// pathutil.DefaultDBPath's environment-aware guess (preferring whichever of
+// Music.app's or iTunes.app's folder already exists). It is evaluated at
+// call time (by NewDatabaseManager), never at package-init time, so tests
+// that set ITUNES_DB_PATH or $HOME after process start are respected.
+func ResolveDBPath(explicit string) (string, error) {
+	path := explicit
+	if path == "" {
+		path = os.Getenv("ITUNES_DB_PATH")
+	}
+	if path != "" {
+		return pathutil.ExpandPath(path)
+	}
+	if profile, err := pathutil.ActiveProfile(); err == nil && profile != "" {
+		return pathutil.ProfileDBPath(profile)
+	}
+	return pathutil.DefaultDBPath()
+}
+
+// NewDatabaseManager opens (creating if necessary) the SQLite database at
+// path and initializes its schema. An empty path is resolved via
+// ResolveDBPath.
+func NewDatabaseManager(path string) (*DatabaseManager, error) {
+	resolved, err := ResolveDBPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if dir := filepath.Dir(resolved); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("database: create directory %q: %w", dir, err)
+		}
+	}
+	db, err := sql.Open("sqlite", resolved)
+	if err != nil {
+		return nil, fmt.Errorf("database: open %q: %w", resolved, err)
+	}
+	m := &DatabaseManager{DB: db, Path: resolved}
+	if err := m.InitSchema(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("database: init schema: %w", err)
+	}
+	m.startWriteQueue()
+	return m, nil
+}
+
+// Close stops the write queue, waiting for any in-flight write to finish,
+// then releases the underlying connection. A read-only manager never
+// started a write queue, so it has nothing to stop.
+func (m *DatabaseManager) Close() error {
+	if !m.readOnly {
+		m.stopWriteQueue()
+	}
+	return m.DB.Close()
+}
+
+// Ping verifies the connection is alive.
+func (m *DatabaseManager) Ping() error {
+	return m.DB.Ping()
+}
+
+// Stats summarizes the library cache for diagnostics and the MCP stats
+// resource.
+type Stats struct {
+	TrackCount    int   `json:"track_count"`
+	PlaylistCount int   `json:"playlist_count"`
+	StationCount  int   `json:"station_count"`
+	SizeBytes     int64 `json:"size_bytes"`
+}
+
+// GetStats returns row counts across the main tables plus the database
+// file's on-disk size, so e.g. OptimizeDatabase can report how much a
+// VACUUM actually reclaimed.
+func (m *DatabaseManager) GetStats() (*Stats, error) {
+	s := &Stats{}
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM tracks`).Scan(&s.TrackCount); err != nil {
+		return nil, err
+	}
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM playlists`).Scan(&s.PlaylistCount); err != nil {
+		return nil, err
+	}
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM radio_stations`).Scan(&s.StationCount); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(m.Path); err == nil {
+		s.SizeBytes = info.Size()
+	}
+	return s, nil
+}
+
+// RatingBuckets are the five-star-scale values Apple Music rates tracks in.
+var RatingBuckets = []int{0, 20, 40, 60, 80, 100}
+
+// GetRatingDistribution returns the number of tracks at each RatingBuckets
+// value via a single GROUP BY on the indexed rating column. Every bucket is
+// present in the result even if it has zero tracks.
+func (m *DatabaseManager) GetRatingDistribution() (map[int]int, error) {
+	dist := make(map[int]int, len(RatingBuckets))
+	for _, b := range RatingBuckets {
+		dist[b] = 0
+	}
+	rows, err := m.DB.Query(`SELECT rating, COUNT(*) FROM tracks GROUP BY rating`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rating, count int
+		if err := rows.Scan(&rating, &count); err != nil {
+			return nil, err
+		}
+		dist[rating] = count
+	}
+	return dist, rows.Err()
+}
+
+// ListTrackKinds returns the distinct track_kind values in the library
+// (e.g. "MPEG audio file", "Internet audio stream") with counts, most
+// common first, so a caller can see what's in the library (music vs.
+// podcasts vs. streams) before filtering a search by SearchFilters.Kind.
+func (m *DatabaseManager) ListTrackKinds() ([]KindCount, error) {
+	rows, err := m.DB.Query(`
+		SELECT track_kind, COUNT(*)
+		FROM tracks
+		GROUP BY track_kind
+		ORDER BY COUNT(*) DESC, track_kind`)
+	if err != nil {
+		return nil, fmt.Errorf("database: list track kinds: %w", err)
+	}
+	defer rows.Close()
+
+	var kinds []KindCount
+	for rows.Next() {
+		var kc KindCount
+		if err := rows.Scan(&kc.Kind, &kc.Count); err != nil {
+			return nil, err
+		}
+		kinds = append(kinds, kc)
+	}
+	return kinds, rows.Err()
+}
+
+// GetTopTracks returns the limit most-played tracks, highest play_count
+// first, ties broken by name.
+func (m *DatabaseManager) GetTopTracks(limit int) ([]Track, error) {
+	rows, err := m.DB.Query(`
+		SELECT t.id, t.persistent_id, t.name,
+			COALESCE(ar.name, ''), COALESCE(al.name, ''), COALESCE(g.name, ''),
+			t.duration, t.track_kind, t.rating, t.starred, t.disliked, t.play_count,
+			t.last_played, t.date_added, t.ranking, t.is_streaming, t.stream_url, t.downloaded
+		FROM tracks t
+		LEFT JOIN artists ar ON ar.id = t.artist_id
+		LEFT JOIN albums al ON al.id = t.album_id
+		LEFT JOIN genres g ON g.id = t.genre_id
+		ORDER BY t.play_count DESC, t.name
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("database: get top tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []Track
+	for rows.Next() {
+		var t Track
+		if err := rows.Scan(&t.ID, &t.PersistentID, &t.Name, &t.Artist, &t.Album, &t.Genre,
+			&t.Duration, &t.TrackKind, &t.Rating, &t.Starred, &t.Disliked, &t.PlayCount,
+			&t.LastPlayed, &t.DateAdded, &t.Ranking, &t.IsStreaming, &t.StreamURL, &t.Downloaded); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, t)
+	}
+	return tracks, rows.Err()
+}
+
+// GetRecentlyAdded returns the limit most recently added tracks, newest
+// first, via idx_tracks_date_added.
+func (m *DatabaseManager) GetRecentlyAdded(limit int) ([]Track, error) {
+	rows, err := m.DB.Query(`
+		SELECT t.id, t.persistent_id, t.name,
+			COALESCE(ar.name, ''), COALESCE(al.name, ''), COALESCE(g.name, ''),
+			t.duration, t.track_kind, t.rating, t.starred, t.disliked, t.play_count,
+			t.last_played, t.date_added, t.ranking, t.is_streaming, t.stream_url, t.downloaded
+		FROM tracks t
+		LEFT JOIN artists ar ON ar.id = t.artist_id
+		LEFT JOIN albums al ON al.id = t.album_id
+		LEFT JOIN genres g ON g.id = t.genre_id
+		ORDER BY t.date_added DESC, t.name
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("database: get recently added: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []Track
+	for rows.Next() {
+		var t Track
+		if err := rows.Scan(&t.ID, &t.PersistentID, &t.Name, &t.Artist, &t.Album, &t.Genre,
+			&t.Duration, &t.TrackKind, &t.Rating, &t.Starred, &t.Disliked, &t.PlayCount,
+			&t.LastPlayed, &t.DateAdded, &t.Ranking, &t.IsStreaming, &t.StreamURL, &t.Downloaded); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, t)
+	}
+	return tracks, rows.Err()
+}
+
+// ArtistPlayCount is one row of GetTopArtists' leaderboard.
+type ArtistPlayCount struct {
+	Artist    string `json:"artist"`
+	PlayCount int    `json:"play_count"`
+}
+
+// GetTopArtists returns the limit artists with the highest summed
+// play_count across all their tracks, highest first.
+func (m *DatabaseManager) GetTopArtists(limit int) ([]ArtistPlayCount, error) {
+	rows, err := m.DB.Query(`
+		SELECT ar.name, SUM(t.play_count) AS total
+		FROM tracks t
+		JOIN artists ar ON ar.id = t.artist_id
+		GROUP BY ar.name
+		ORDER BY total DESC, ar.name
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("database: get top artists: %w", err)
+	}
+	defer rows.Close()
+
+	var artists []ArtistPlayCount
+	for rows.Next() {
+		var a ArtistPlayCount
+		if err := rows.Scan(&a.Artist, &a.PlayCount); err != nil {
+			return nil, err
+		}
+		artists = append(artists, a)
+	}
+	return artists, rows.Err()
+}
+
+// GetStarredBreakdown returns the count of starred vs unstarred tracks.
+func (m *DatabaseManager) GetStarredBreakdown() (starred int, unstarred int, err error) {
+	if err = m.DB.QueryRow(`SELECT COUNT(*) FROM tracks WHERE starred`).Scan(&starred); err != nil {
+		return 0, 0, err
+	}
+	if err = m.DB.QueryRow(`SELECT COUNT(*) FROM tracks WHERE NOT starred`).Scan(&unstarred); err != nil {
+		return 0, 0, err
+	}
+	return starred, unstarred, nil
+}
@@ -0,0 +1,27 @@
+package database
+
+import "database/sql"
+
+// GetMeta returns the value stored under key in the metadata table, and
+// false if no row exists for it.
+func (m *DatabaseManager) GetMeta(key string) (string, bool, error) {
+	var value string
+	err := m.DB.QueryRow(`SELECT value FROM metadata WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// SetMeta upserts value under key in the metadata table.
+func (m *DatabaseManager) SetMeta(key, value string) error {
+	return m.enqueueWrite(func() error {
+		_, err := m.DB.Exec(`INSERT INTO metadata (key, value) VALUES (?, ?)
+			ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+			key, value)
+		return err
+	})
+}
@@ -0,0 +1,27 @@
+package database
+
+import "testing"
+
+func TestMetaRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, ok, err := db.GetMeta("missing"); err != nil || ok {
+		t.Fatalf("GetMeta(missing) = ok=%v err=%v, want ok=false err=nil", ok, err)
+	}
+
+	if err := db.SetMeta("key", "value"); err != nil {
+		t.Fatalf("SetMeta: %v", err)
+	}
+	value, ok, err := db.GetMeta("key")
+	if err != nil || !ok || value != "value" {
+		t.Fatalf("GetMeta(key) = %q, %v, %v, want value, true, nil", value, ok, err)
+	}
+
+	if err := db.SetMeta("key", "updated"); err != nil {
+		t.Fatalf("SetMeta (update): %v", err)
+	}
+	value, _, _ = db.GetMeta("key")
+	if value != "updated" {
+		t.Fatalf("got %q, want updated after overwrite", value)
+	}
+}
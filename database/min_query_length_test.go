@@ -0,0 +1,60 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSearchTracksMinQueryLengthBoundary(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "ab", Artist: "Artist"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	sm := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	sm.MinQueryLength = 3
+
+	if _, err := sm.SearchTracks("ab", nil); !errors.Is(err, ErrQueryTooShort) {
+		t.Fatalf("SearchTracks(\"ab\") error = %v, want ErrQueryTooShort (below threshold)", err)
+	}
+	if _, err := sm.SearchTracks("abc", nil); err != nil {
+		t.Fatalf("SearchTracks(\"abc\") error = %v, want nil (at threshold)", err)
+	}
+	if _, err := sm.SearchTracks("abcd", nil); err != nil {
+		t.Fatalf("SearchTracks(\"abcd\") error = %v, want nil (above threshold)", err)
+	}
+}
+
+func TestSearchTracksMinQueryLengthBypassedForFilterOnlySearch(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "Song", Artist: "Artist"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	sm := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	sm.MinQueryLength = 10
+
+	tracks, err := sm.SearchTracks("", &SearchFilters{Artist: "Artist"})
+	if err != nil {
+		t.Fatalf("SearchTracks(\"\", filters) error = %v, want nil (filter-only search bypasses min length)", err)
+	}
+	if len(tracks) != 1 {
+		t.Fatalf("got %d tracks, want 1", len(tracks))
+	}
+}
+
+func TestMinQueryLengthFromEnvDefault(t *testing.T) {
+	db := newTestDB(t)
+	sm := NewSearchManager(db)
+	if sm.MinQueryLength != DefaultMinQueryLength {
+		t.Fatalf("MinQueryLength = %d, want DefaultMinQueryLength (%d) when ITUNES_MIN_QUERY_LENGTH is unset", sm.MinQueryLength, DefaultMinQueryLength)
+	}
+}
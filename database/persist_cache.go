@@ -0,0 +1,118 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// DefaultCacheTTL bounds how old a disk-persisted search cache entry may be
+// before LoadPersistedCache discards it instead of reviving it into memory.
+// It has no effect on entries already sitting in the in-memory cache during
+// normal operation; those are still invalidated wholesale by ClearCache.
+const DefaultCacheTTL = 24 * time.Hour
+
+// persistCacheEnvVar opts a SearchManager into backing its in-memory search
+// cache with the on-disk search_cache table, so a restarted process isn't
+// cold on its first search. Off by default: it trades freshness for
+// warmth, and every write-through is an extra disk write per distinct
+// query/filter combination searched.
+const persistCacheEnvVar = "ITUNES_PERSIST_SEARCH_CACHE"
+
+// cacheTTLEnvVar names the environment variable overriding DefaultCacheTTL,
+// parsed with time.ParseDuration (e.g. "1h", "30m").
+const cacheTTLEnvVar = "ITUNES_SEARCH_CACHE_TTL"
+
+func persistCacheEnabledFromEnv() bool {
+	return os.Getenv(persistCacheEnvVar) != ""
+}
+
+func cacheTTLFromEnv() time.Duration {
+	if v := os.Getenv(cacheTTLEnvVar); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultCacheTTL
+}
+
+// LoadPersistedCache reads every row out of the search_cache table into
+// sm's in-memory cache, skipping (and deleting) any entry older than
+// sm.CacheTTL. NewSearchManagerWithLimit calls this automatically when
+// PersistCache is enabled. A row that fails to decode is skipped rather
+// than failing the whole load, since losing one stale cache entry is
+// harmless but refusing to start serving searches over it would not be.
+func (sm *SearchManager) LoadPersistedCache() error {
+	rows, err := sm.DB.DB.Query(`SELECT cache_key, results_json, cached_at FROM search_cache`)
+	if err != nil {
+		return fmt.Errorf("database: load persisted search cache: %w", err)
+	}
+	defer rows.Close()
+
+	loaded := make(map[string][]Track)
+	var expiredKeys []string
+	for rows.Next() {
+		var key, resultsJSON string
+		var cachedAt time.Time
+		if err := rows.Scan(&key, &resultsJSON, &cachedAt); err != nil {
+			slog.Warn("database: skip corrupt search_cache row", "error", err)
+			continue
+		}
+		if sm.CacheTTL > 0 && time.Since(cachedAt) > sm.CacheTTL {
+			expiredKeys = append(expiredKeys, key)
+			continue
+		}
+		var tracks []Track
+		if err := json.Unmarshal([]byte(resultsJSON), &tracks); err != nil {
+			slog.Warn("database: skip corrupt search_cache row", "key", key, "error", err)
+			continue
+		}
+		loaded[key] = tracks
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("database: load persisted search cache: %w", err)
+	}
+
+	sm.cacheMu.Lock()
+	for key, tracks := range loaded {
+		sm.cache[key] = tracks
+	}
+	sm.cacheMu.Unlock()
+
+	for _, key := range expiredKeys {
+		if _, err := sm.DB.DB.Exec(`DELETE FROM search_cache WHERE cache_key = ?`, key); err != nil {
+			slog.Warn("database: delete expired search_cache row", "key", key, "error", err)
+		}
+	}
+	return nil
+}
+
+// persistCacheEntry writes key/tracks through to the search_cache table.
+// Errors are logged rather than returned, same as LogSearch: a
+// cache-persistence failure (e.g. the database is open read-only) should
+// never fail the search that triggered it.
+func (sm *SearchManager) persistCacheEntry(key string, tracks []Track) {
+	data, err := json.Marshal(tracks)
+	if err != nil {
+		slog.Warn("database: marshal search cache entry", "key", key, "error", err)
+		return
+	}
+	if _, err := sm.DB.DB.Exec(`
+		INSERT INTO search_cache (cache_key, results_json, cached_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(cache_key) DO UPDATE SET results_json = excluded.results_json, cached_at = excluded.cached_at`,
+		key, string(data)); err != nil {
+		slog.Warn("database: persist search cache entry", "key", key, "error", err)
+	}
+}
+
+// clearPersistedCache deletes every row from the search_cache table. Called
+// by ClearCache when PersistCache is enabled, so a write that invalidates
+// the in-memory cache doesn't leave stale results on disk to be revived by
+// LoadPersistedCache after a later restart.
+func (sm *SearchManager) clearPersistedCache() {
+	if _, err := sm.DB.DB.Exec(`DELETE FROM search_cache`); err != nil {
+		slog.Warn("database: clear persisted search cache", "error", err)
+	}
+}
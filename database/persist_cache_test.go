@@ -0,0 +1,88 @@
+package database
+
+import "testing"
+
+// TestPersistentSearchCacheSurvivesSimulatedRestart confirms a cached
+// result written by one SearchManager is loaded by a second one
+// constructed against the same database, simulating a process restart
+// without losing the warm cache.
+func TestPersistentSearchCacheSurvivesSimulatedRestart(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{{PersistentID: "p1", Name: "Song", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	t.Setenv("ITUNES_PERSIST_SEARCH_CACHE", "1")
+
+	sm1 := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	if _, err := sm1.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+
+	sm2 := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	tracks, err := sm2.SearchTracks("Song", nil)
+	if err != nil {
+		t.Fatalf("SearchTracks after simulated restart: %v", err)
+	}
+	if len(tracks) != 1 {
+		t.Fatalf("got %d tracks, want 1", len(tracks))
+	}
+	if !sm2.LastMetrics().CacheHit {
+		t.Fatal("expected a cache hit from the cache persisted across the simulated restart")
+	}
+}
+
+// TestPersistentSearchCacheDiscardsExpiredEntryOnLoad confirms an entry
+// older than CacheTTL isn't revived into memory.
+func TestPersistentSearchCacheDiscardsExpiredEntryOnLoad(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{{PersistentID: "p1", Name: "Song", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	t.Setenv("ITUNES_PERSIST_SEARCH_CACHE", "1")
+	sm1 := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	if _, err := sm1.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+
+	t.Setenv("ITUNES_SEARCH_CACHE_TTL", "1ns")
+	sm2 := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	if _, err := sm2.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("SearchTracks after simulated restart: %v", err)
+	}
+	if sm2.LastMetrics().CacheHit {
+		t.Fatal("expected the expired persisted entry to be discarded, not revived")
+	}
+}
+
+// TestClearCacheClearsPersistedEntriesToo confirms ClearCache removes
+// disk-persisted entries, so a later restart doesn't revive a result a
+// write already invalidated.
+func TestClearCacheClearsPersistedEntriesToo(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{{PersistentID: "p1", Name: "Song", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	t.Setenv("ITUNES_PERSIST_SEARCH_CACHE", "1")
+	sm1 := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	if _, err := sm1.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	sm1.ClearCache()
+
+	sm2 := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	if _, err := sm2.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("SearchTracks after simulated restart: %v", err)
+	}
+	if sm2.LastMetrics().CacheHit {
+		t.Fatal("expected no cache hit: ClearCache should have removed the persisted entry too")
+	}
+}
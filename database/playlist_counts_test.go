@@ -0,0 +1,67 @@
+package database
+
+import "testing"
+
+func TestSyncPlaylistUpdatesTrackCount(t *testing.T) {
+	db := newTestDB(t)
+
+	resp := &RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "One"},
+			{PersistentID: "p2", Name: "Two"},
+			{PersistentID: "p3", Name: "Three"},
+		},
+	}
+	if _, err := db.PopulateFromRefreshScript(resp); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	trackIDs := make(map[string]int64)
+	for _, pid := range []string{"p1", "p2", "p3"} {
+		row, err := db.GetTrackByPersistentID(pid)
+		if err != nil {
+			t.Fatalf("GetTrackByPersistentID(%q): %v", pid, err)
+		}
+		trackIDs[pid] = row.ID
+	}
+
+	var playlistID int64
+	if err := db.DB.QueryRow(`INSERT INTO playlists (persistent_id, name) VALUES ('pl1', 'Favorites') RETURNING id`).Scan(&playlistID); err != nil {
+		t.Fatalf("insert playlist: %v", err)
+	}
+
+	if _, err := db.SyncPlaylist(playlistID, []int64{trackIDs["p1"], trackIDs["p2"], trackIDs["p3"]}, false); err != nil {
+		t.Fatalf("SyncPlaylist: %v", err)
+	}
+	assertTrackCount(t, db, playlistID, 3)
+
+	if _, err := db.SyncPlaylist(playlistID, []int64{trackIDs["p1"]}, false); err != nil {
+		t.Fatalf("SyncPlaylist (shrink): %v", err)
+	}
+	assertTrackCount(t, db, playlistID, 1)
+}
+
+func TestReconcilePlaylistCountsFixesStaleCounts(t *testing.T) {
+	db := newTestDB(t)
+
+	var playlistID int64
+	if err := db.DB.QueryRow(`INSERT INTO playlists (persistent_id, name, track_count) VALUES ('pl1', 'Favorites', 99) RETURNING id`).Scan(&playlistID); err != nil {
+		t.Fatalf("insert playlist: %v", err)
+	}
+
+	if err := db.ReconcilePlaylistCounts(); err != nil {
+		t.Fatalf("ReconcilePlaylistCounts: %v", err)
+	}
+	assertTrackCount(t, db, playlistID, 0)
+}
+
+func assertTrackCount(t *testing.T, db *DatabaseManager, playlistID int64, want int) {
+	t.Helper()
+	var got int
+	if err := db.DB.QueryRow(`SELECT track_count FROM playlists WHERE id = ?`, playlistID).Scan(&got); err != nil {
+		t.Fatalf("query track_count: %v", err)
+	}
+	if got != want {
+		t.Fatalf("track_count = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package database
+
+import "testing"
+
+func playlistTrackCount(t *testing.T, db *DatabaseManager, playlistID int64) int {
+	t.Helper()
+	var n int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM playlist_tracks WHERE playlist_id = ?`, playlistID).Scan(&n); err != nil {
+		t.Fatalf("count playlist_tracks: %v", err)
+	}
+	return n
+}
+
+func TestPopulateFromRefreshScriptReplaceDropsStaleAssociations(t *testing.T) {
+	db := newTestDB(t)
+
+	seed := func(trackIDs []string) {
+		if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+			Tracks: []RefreshTrack{
+				{PersistentID: "p1", Name: "One"},
+				{PersistentID: "p2", Name: "Two"},
+			},
+			Playlists: []RefreshPlaylist{
+				{PersistentID: "pl1", Name: "Mix", TrackIDs: trackIDs},
+			},
+		}); err != nil {
+			t.Fatalf("PopulateFromRefreshScript: %v", err)
+		}
+	}
+
+	seed([]string{"p1", "p2"})
+	seed([]string{"p1"})
+
+	var playlistID int64
+	if err := db.DB.QueryRow(`SELECT id FROM playlists WHERE persistent_id = ?`, "pl1").Scan(&playlistID); err != nil {
+		t.Fatalf("look up playlist id: %v", err)
+	}
+	if n := playlistTrackCount(t, db, playlistID); n != 1 {
+		t.Fatalf("playlist_tracks rows = %d, want 1 (stale p2 association should be dropped)", n)
+	}
+
+	page, err := db.ListPlaylists(nil, false, 0, 0)
+	if err != nil {
+		t.Fatalf("ListPlaylists: %v", err)
+	}
+	if page.Playlists[0].TrackCount != 1 {
+		t.Fatalf("TrackCount = %d, want 1", page.Playlists[0].TrackCount)
+	}
+}
+
+func TestPopulateFromRefreshScriptMergeKeepsExistingAssociations(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "One"},
+			{PersistentID: "p2", Name: "Two"},
+		},
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "Mix", TrackIDs: []string{"p1"}},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript (seed): %v", err)
+	}
+
+	if _, err := db.PopulateFromRefreshScriptWithOptions(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "One"},
+			{PersistentID: "p2", Name: "Two"},
+		},
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "Mix", TrackIDs: []string{"p1", "p2"}},
+		},
+	}, PopulateOptions{MergePlaylists: true}); err != nil {
+		t.Fatalf("PopulateFromRefreshScriptWithOptions (merge): %v", err)
+	}
+
+	// Running the same merge scan twice must not duplicate the p1
+	// association thanks to playlist_tracks' (playlist_id, track_id)
+	// primary key.
+	if _, err := db.PopulateFromRefreshScriptWithOptions(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "One"},
+			{PersistentID: "p2", Name: "Two"},
+		},
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "Mix", TrackIDs: []string{"p1", "p2"}},
+		},
+	}, PopulateOptions{MergePlaylists: true}); err != nil {
+		t.Fatalf("PopulateFromRefreshScriptWithOptions (merge again): %v", err)
+	}
+
+	var playlistID int64
+	if err := db.DB.QueryRow(`SELECT id FROM playlists WHERE persistent_id = ?`, "pl1").Scan(&playlistID); err != nil {
+		t.Fatalf("look up playlist id: %v", err)
+	}
+	if n := playlistTrackCount(t, db, playlistID); n != 2 {
+		t.Fatalf("playlist_tracks rows = %d, want 2 (p1 kept, p2 added, no duplicates)", n)
+	}
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetPlaylistByPersistentIDMissingReturnsErrPlaylistNotFound(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := db.GetPlaylistByPersistentID("does-not-exist")
+	if !errors.Is(err, ErrPlaylistNotFound) {
+		t.Fatalf("got %v, want ErrPlaylistNotFound", err)
+	}
+}
+
+func TestGetPlaylistTracksEmptyPlaylistReturnsNoError(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Playlists: []RefreshPlaylist{{PersistentID: "pl1", Name: "Empty"}},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	playlist, err := db.GetPlaylistByPersistentID("pl1")
+	if err != nil {
+		t.Fatalf("GetPlaylistByPersistentID: %v", err)
+	}
+
+	tracks, err := db.GetPlaylistTracks(playlist.ID)
+	if err != nil {
+		t.Fatalf("GetPlaylistTracks: %v", err)
+	}
+	if len(tracks) != 0 {
+		t.Fatalf("got %d tracks, want 0 for an empty playlist", len(tracks))
+	}
+}
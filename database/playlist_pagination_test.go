@@ -0,0 +1,54 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestListPlaylistsPagingCoversAllWithoutOverlap(t *testing.T) {
+	db := newTestDB(t)
+
+	const total = 37
+	playlists := make([]RefreshPlaylist, total)
+	for i := 0; i < total; i++ {
+		playlists[i] = RefreshPlaylist{
+			PersistentID: fmt.Sprintf("pl%03d", i),
+			Name:         fmt.Sprintf("Playlist %03d", i),
+		}
+	}
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{Playlists: playlists}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	const pageSize = 10
+	seen := make(map[string]bool, total)
+	for offset := 0; ; offset += pageSize {
+		page, err := db.ListPlaylists(nil, false, pageSize, offset)
+		if err != nil {
+			t.Fatalf("ListPlaylists(limit=%d, offset=%d): %v", pageSize, offset, err)
+		}
+		if page.Total != total {
+			t.Fatalf("Total = %d, want %d", page.Total, total)
+		}
+		if len(page.Playlists) == 0 {
+			break
+		}
+		for _, p := range page.Playlists {
+			if seen[p.PersistentID] {
+				t.Fatalf("playlist %q returned on more than one page", p.PersistentID)
+			}
+			seen[p.PersistentID] = true
+		}
+	}
+	if len(seen) != total {
+		t.Fatalf("paged through %d playlists, want %d", len(seen), total)
+	}
+
+	unpaged, err := db.ListPlaylists(nil, false, 0, 0)
+	if err != nil {
+		t.Fatalf("ListPlaylists(unpaged): %v", err)
+	}
+	if len(unpaged.Playlists) != total {
+		t.Fatalf("unpaged call returned %d playlists, want %d", len(unpaged.Playlists), total)
+	}
+}
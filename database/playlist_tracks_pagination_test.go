@@ -0,0 +1,67 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGetPlaylistTracksPagingCoversAllWithoutOverlap(t *testing.T) {
+	db := newTestDB(t)
+
+	const total = 45
+	tracks := make([]RefreshTrack, total)
+	trackIDs := make([]string, total)
+	for i := 0; i < total; i++ {
+		id := fmt.Sprintf("t%03d", i)
+		tracks[i] = RefreshTrack{PersistentID: id, Name: fmt.Sprintf("Track %03d", i)}
+		trackIDs[i] = id
+	}
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: tracks,
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "Big Playlist", TrackIDs: trackIDs},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	playlist, err := db.GetPlaylistByPersistentID("pl1")
+	if err != nil {
+		t.Fatalf("GetPlaylistByPersistentID: %v", err)
+	}
+
+	const pageSize = 10
+	seen := make(map[string]bool, total)
+	for offset := 0; ; offset += pageSize {
+		page, err := db.GetPlaylistTracksPage(playlist.ID, pageSize, offset)
+		if err != nil {
+			t.Fatalf("GetPlaylistTracksPage(limit=%d, offset=%d): %v", pageSize, offset, err)
+		}
+		if page.Total != total {
+			t.Fatalf("Total = %d, want %d", page.Total, total)
+		}
+		if len(page.Tracks) == 0 {
+			break
+		}
+		for _, tr := range page.Tracks {
+			if seen[tr.PersistentID] {
+				t.Fatalf("track %q returned on more than one page", tr.PersistentID)
+			}
+			seen[tr.PersistentID] = true
+		}
+	}
+	if len(seen) != total {
+		t.Fatalf("paged through %d tracks, want %d", len(seen), total)
+	}
+
+	unpaged, err := db.GetPlaylistTracksPage(playlist.ID, 0, 0)
+	if err != nil {
+		t.Fatalf("GetPlaylistTracksPage(unpaged): %v", err)
+	}
+	if len(unpaged.Tracks) != total {
+		t.Fatalf("unpaged call returned %d tracks, want %d", len(unpaged.Tracks), total)
+	}
+	if unpaged.Total != total {
+		t.Fatalf("unpaged Total = %d, want %d", unpaged.Total, total)
+	}
+}
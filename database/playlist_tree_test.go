@@ -0,0 +1,46 @@
+package database
+
+import "testing"
+
+func TestListPlaylistTreeBuildsTwoLevelTree(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "t1", Name: "One", Artist: "Artist"},
+		},
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "folder1", Name: "Moods", SpecialKind: "folder"},
+			{PersistentID: "pl1", Name: "Chill", ParentID: "folder1", TrackIDs: []string{"t1"}},
+			{PersistentID: "pl2", Name: "Favorites"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	tree, err := db.ListPlaylistTree()
+	if err != nil {
+		t.Fatalf("ListPlaylistTree: %v", err)
+	}
+	if len(tree) != 2 {
+		t.Fatalf("got %d top-level nodes, want 2 (Moods folder, Favorites)", len(tree))
+	}
+
+	var folder *PlaylistNode
+	for _, n := range tree {
+		if n.PersistentID == "folder1" {
+			folder = n
+		}
+	}
+	if folder == nil {
+		t.Fatal("Moods folder missing from top-level nodes")
+	}
+	if folder.SpecialKind != "folder" {
+		t.Fatalf("SpecialKind = %q, want \"folder\"", folder.SpecialKind)
+	}
+	if len(folder.Children) != 1 || folder.Children[0].PersistentID != "pl1" {
+		t.Fatalf("folder.Children = %+v, want just pl1", folder.Children)
+	}
+	if folder.Children[0].TrackCount != 1 {
+		t.Fatalf("pl1.TrackCount = %d, want 1", folder.Children[0].TrackCount)
+	}
+}
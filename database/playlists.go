@@ -0,0 +1,513 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// PlaylistPage is ListPlaylists's return shape: the page of playlists
+// actually returned plus Total, the count matching smart across the whole
+// table, so callers can page through a large library without guessing
+// when they've reached the end.
+type PlaylistPage struct {
+	Playlists []Playlist `json:"playlists"`
+	Total     int        `json:"total"`
+}
+
+// ListPlaylists returns the user playlists, ordered by name. smart, when
+// non-nil, restricts the result to only (true) or only (false) smart
+// playlists. includeSpecial, when false, restricts the result to
+// special_kind = 'none' as before; when true, system playlists like
+// "Recently Added" are included too. limit <= 0 returns every matching
+// playlist, unpaged, for backward compatibility; limit > 0 returns at most
+// limit playlists starting at offset.
+func (m *DatabaseManager) ListPlaylists(smart *bool, includeSpecial bool, limit, offset int) (*PlaylistPage, error) {
+	where := `WHERE 1 = 1`
+	var args []interface{}
+	if !includeSpecial {
+		where += ` AND special_kind = 'none'`
+	}
+	if smart != nil {
+		where += ` AND smart = ?`
+		args = append(args, *smart)
+	}
+
+	var total int
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM playlists `+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("database: count playlists: %w", err)
+	}
+
+	query := `SELECT id, persistent_id, name, special_kind, track_count, parent_id, smart FROM playlists ` + where + ` ORDER BY name`
+	if limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, offset)
+	}
+
+	rows, err := m.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database: list playlists: %w", err)
+	}
+	defer rows.Close()
+
+	var playlists []Playlist
+	for rows.Next() {
+		var p Playlist
+		if err := rows.Scan(&p.ID, &p.PersistentID, &p.Name, &p.SpecialKind, &p.TrackCount, &p.ParentID, &p.Smart); err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &PlaylistPage{Playlists: playlists, Total: total}, nil
+}
+
+// PlaylistNode is one node of the tree ListPlaylistTree builds: a playlist
+// (which may itself be a folder, special_kind = "folder") plus its direct
+// children.
+type PlaylistNode struct {
+	Playlist
+	Children []*PlaylistNode `json:"children,omitempty"`
+}
+
+// ListPlaylistTree returns every playlist, including folders, nested under
+// their parent_id into a tree. Top-level playlists/folders (parent_id NULL)
+// are the returned slice; everything else hangs off some node's Children.
+// Unlike ListPlaylists, this includes folder rows (special_kind = 'folder'),
+// since a tree without its folders isn't useful.
+func (m *DatabaseManager) ListPlaylistTree() ([]*PlaylistNode, error) {
+	rows, err := m.DB.Query(`
+		SELECT id, persistent_id, name, special_kind, track_count, parent_id
+		FROM playlists
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("database: list playlist tree: %w", err)
+	}
+	defer rows.Close()
+
+	nodes := make(map[int64]*PlaylistNode)
+	var order []int64
+	for rows.Next() {
+		var p Playlist
+		if err := rows.Scan(&p.ID, &p.PersistentID, &p.Name, &p.SpecialKind, &p.TrackCount, &p.ParentID); err != nil {
+			return nil, err
+		}
+		nodes[p.ID] = &PlaylistNode{Playlist: p}
+		order = append(order, p.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var roots []*PlaylistNode
+	for _, id := range order {
+		node := nodes[id]
+		if node.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		parent, ok := nodes[*node.ParentID]
+		if !ok {
+			// Dangling parent_id (parent row missing/deleted): surface the
+			// node at the top level rather than dropping it silently.
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return roots, nil
+}
+
+// GetPlaylistByPersistentID looks up a playlist by its Apple Music
+// persistent ID, or ErrPlaylistNotFound if no such playlist exists.
+func (m *DatabaseManager) GetPlaylistByPersistentID(persistentID string) (*Playlist, error) {
+	var p Playlist
+	err := m.DB.QueryRow(`
+		SELECT id, persistent_id, name, special_kind, track_count, parent_id, smart
+		FROM playlists WHERE persistent_id = ?`, persistentID).
+		Scan(&p.ID, &p.PersistentID, &p.Name, &p.SpecialKind, &p.TrackCount, &p.ParentID, &p.Smart)
+	if err == sql.ErrNoRows {
+		return nil, ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database: get playlist %q: %w", persistentID, err)
+	}
+	return &p, nil
+}
+
+// TrackPage is GetPlaylistTracksPage's return shape: the page of tracks
+// actually returned plus Total, the playlist's full track count, so
+// callers can page through a large playlist without loading it all into
+// memory at once or guessing when they've reached the end.
+type TrackPage struct {
+	Tracks []Track `json:"tracks"`
+	Total  int     `json:"total"`
+}
+
+// GetPlaylistTracksPage returns the tracks belonging to playlistID,
+// ordered by their stored position, alongside the playlist's total track
+// count. limit <= 0 returns every track, unpaged, for backward
+// compatibility with GetPlaylistTracks; limit > 0 returns at most limit
+// tracks starting at offset, so a caller can stream through a very large
+// playlist in bounded-size pages instead of allocating one big slice for
+// it.
+func (m *DatabaseManager) GetPlaylistTracksPage(playlistID int64, limit, offset int) (*TrackPage, error) {
+	var total int
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM playlist_tracks WHERE playlist_id = ?`, playlistID).Scan(&total); err != nil {
+		return nil, fmt.Errorf("database: count playlist tracks: %w", err)
+	}
+
+	query := `
+		SELECT t.id, t.persistent_id, t.name,
+			COALESCE(ar.name, ''), COALESCE(al.name, ''), COALESCE(g.name, ''),
+			t.duration, t.track_kind, t.rating, t.starred, t.disliked, t.play_count,
+			t.last_played, t.date_added, t.ranking, t.is_streaming, t.stream_url, t.downloaded
+		FROM playlist_tracks pt
+		JOIN tracks t ON t.id = pt.track_id
+		LEFT JOIN artists ar ON ar.id = t.artist_id
+		LEFT JOIN albums al ON al.id = t.album_id
+		LEFT JOIN genres g ON g.id = t.genre_id
+		WHERE pt.playlist_id = ?
+		ORDER BY pt.position`
+	args := []interface{}{playlistID}
+	if limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, offset)
+	}
+
+	rows, err := m.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database: get playlist tracks page: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []Track
+	for rows.Next() {
+		var t Track
+		if err := rows.Scan(&t.ID, &t.PersistentID, &t.Name, &t.Artist, &t.Album, &t.Genre,
+			&t.Duration, &t.TrackKind, &t.Rating, &t.Starred, &t.Disliked, &t.PlayCount,
+			&t.LastPlayed, &t.DateAdded, &t.Ranking, &t.IsStreaming, &t.StreamURL, &t.Downloaded); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &TrackPage{Tracks: tracks, Total: total}, nil
+}
+
+// GetPlaylistTracks returns the tracks belonging to playlistID, ordered by
+// their stored position.
+func (m *DatabaseManager) GetPlaylistTracks(playlistID int64) ([]Track, error) {
+	rows, err := m.DB.Query(`
+		SELECT t.id, t.persistent_id, t.name,
+			COALESCE(ar.name, ''), COALESCE(al.name, ''), COALESCE(g.name, ''),
+			t.duration, t.track_kind, t.rating, t.starred, t.disliked, t.play_count,
+			t.last_played, t.date_added, t.ranking, t.is_streaming, t.stream_url, t.downloaded
+		FROM playlist_tracks pt
+		JOIN tracks t ON t.id = pt.track_id
+		LEFT JOIN artists ar ON ar.id = t.artist_id
+		LEFT JOIN albums al ON al.id = t.album_id
+		LEFT JOIN genres g ON g.id = t.genre_id
+		WHERE pt.playlist_id = ?
+		ORDER BY pt.position`, playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("database: get playlist tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []Track
+	for rows.Next() {
+		var t Track
+		if err := rows.Scan(&t.ID, &t.PersistentID, &t.Name, &t.Artist, &t.Album, &t.Genre,
+			&t.Duration, &t.TrackKind, &t.Rating, &t.Starred, &t.Disliked, &t.PlayCount,
+			&t.LastPlayed, &t.DateAdded, &t.Ranking, &t.IsStreaming, &t.StreamURL, &t.Downloaded); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, t)
+	}
+	return tracks, rows.Err()
+}
+
+// BatchInsertPlaylistTracks associates trackIDs with playlistID in the
+// given order, appending after any existing rows.
+func (m *DatabaseManager) BatchInsertPlaylistTracks(playlistID int64, trackIDs []int64) error {
+	return m.enqueueWrite(func() error {
+		tx, err := m.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var pos int
+		if err := tx.QueryRow(`SELECT COALESCE(MAX(position), 0) FROM playlist_tracks WHERE playlist_id = ?`, playlistID).Scan(&pos); err != nil {
+			return err
+		}
+		stmt, err := tx.Prepare(`INSERT OR IGNORE INTO playlist_tracks (playlist_id, track_id, position) VALUES (?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, trackID := range trackIDs {
+			pos++
+			if _, err := stmt.Exec(playlistID, trackID, pos); err != nil {
+				return fmt.Errorf("database: insert playlist track: %w", err)
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// SyncPlaylistResult is SyncPlaylist's change set: the track IDs added and
+// removed by the sync, and the resulting track_count.
+type SyncPlaylistResult struct {
+	Added      []int64 `json:"added"`
+	Removed    []int64 `json:"removed"`
+	FinalCount int     `json:"final_count"`
+}
+
+// SyncPlaylist replaces playlistID's track associations with tracks, in
+// order, used by the migrate tool to reconcile membership after a refresh.
+// When dryRun is true, the computed change is rolled back instead of
+// committed, so callers can preview a sync before applying it.
+func (m *DatabaseManager) SyncPlaylist(playlistID int64, trackIDs []int64, dryRun bool) (*SyncPlaylistResult, error) {
+	var result *SyncPlaylistResult
+	err := m.enqueueWrite(func() error {
+		tx, err := m.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		before, err := playlistTrackIDsTx(tx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM playlist_tracks WHERE playlist_id = ?`, playlistID); err != nil {
+			return err
+		}
+		stmt, err := tx.Prepare(`INSERT INTO playlist_tracks (playlist_id, track_id, position) VALUES (?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for i, trackID := range trackIDs {
+			if _, err := stmt.Exec(playlistID, trackID, i+1); err != nil {
+				return fmt.Errorf("database: sync playlist track: %w", err)
+			}
+		}
+		if _, err := tx.Exec(`UPDATE playlists SET track_count = ? WHERE id = ?`, len(trackIDs), playlistID); err != nil {
+			return fmt.Errorf("database: update playlist track_count: %w", err)
+		}
+
+		result = diffPlaylistTrackIDs(before, trackIDs)
+		result.FinalCount = len(trackIDs)
+
+		if dryRun {
+			return nil
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// playlistTrackIDsTx reads playlistID's current track IDs within tx, so
+// SyncPlaylist can diff them against the new set before overwriting.
+func playlistTrackIDsTx(tx *sql.Tx, playlistID int64) ([]int64, error) {
+	rows, err := tx.Query(`SELECT track_id FROM playlist_tracks WHERE playlist_id = ?`, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// diffPlaylistTrackIDs computes which track IDs in before/after are new or
+// gone, for SyncPlaylistResult.
+func diffPlaylistTrackIDs(before, after []int64) *SyncPlaylistResult {
+	beforeSet := make(map[int64]bool, len(before))
+	for _, id := range before {
+		beforeSet[id] = true
+	}
+	afterSet := make(map[int64]bool, len(after))
+	for _, id := range after {
+		afterSet[id] = true
+	}
+	result := &SyncPlaylistResult{}
+	for _, id := range after {
+		if !beforeSet[id] {
+			result.Added = append(result.Added, id)
+		}
+	}
+	for _, id := range before {
+		if !afterSet[id] {
+			result.Removed = append(result.Removed, id)
+		}
+	}
+	return result
+}
+
+// SetPlaylistTracksByPersistentID replaces playlistPersistentID's track
+// associations with trackPersistentIDs, in order, resolving persistent IDs
+// to internal track IDs and updating track_count in one transaction. This
+// is the natural entry point for external callers, who only ever know
+// persistent IDs and shouldn't have to resolve them before calling
+// SyncPlaylist themselves. Persistent IDs that don't match any track are
+// skipped and returned so the caller can report them.
+func (m *DatabaseManager) SetPlaylistTracksByPersistentID(playlistPersistentID string, trackPersistentIDs []string) (notFound []string, err error) {
+	playlist, err := m.GetPlaylistByPersistentID(playlistPersistentID)
+	if err != nil {
+		return nil, err
+	}
+	if playlist.Smart {
+		return nil, fmt.Errorf("database: set playlist tracks %q: %w", playlistPersistentID, ErrSmartPlaylist)
+	}
+
+	err = m.enqueueWrite(func() error {
+		tx, err := m.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var trackIDs []int64
+		for _, pid := range trackPersistentIDs {
+			var id int64
+			err := tx.QueryRow(`SELECT id FROM tracks WHERE persistent_id = ?`, pid).Scan(&id)
+			if err != nil {
+				notFound = append(notFound, pid)
+				continue
+			}
+			trackIDs = append(trackIDs, id)
+		}
+
+		if _, err := tx.Exec(`DELETE FROM playlist_tracks WHERE playlist_id = ?`, playlist.ID); err != nil {
+			return err
+		}
+		stmt, err := tx.Prepare(`INSERT INTO playlist_tracks (playlist_id, track_id, position) VALUES (?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for i, trackID := range trackIDs {
+			if _, err := stmt.Exec(playlist.ID, trackID, i+1); err != nil {
+				return fmt.Errorf("database: set playlist track: %w", err)
+			}
+		}
+		if _, err := tx.Exec(`UPDATE playlists SET track_count = ? WHERE id = ?`, len(trackIDs), playlist.ID); err != nil {
+			return fmt.Errorf("database: update playlist track_count: %w", err)
+		}
+		return tx.Commit()
+	})
+	return notFound, err
+}
+
+// ReorderPlaylistTrack moves the track at position fromPos in playlistID to
+// toPos, renumbering everything in between. It uses a temporary negative
+// offset for the moved rows because playlist_tracks has a UNIQUE(playlist_id,
+// position) constraint that a naive in-place renumber would violate
+// (two rows briefly wanting the same position mid-update).
+func (m *DatabaseManager) ReorderPlaylistTrack(playlistID int64, fromPos, toPos int) error {
+	if fromPos == toPos {
+		return nil
+	}
+
+	return m.enqueueWrite(func() error {
+		tx, err := m.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var trackID int64
+		if err := tx.QueryRow(`SELECT track_id FROM playlist_tracks WHERE playlist_id = ? AND position = ?`, playlistID, fromPos).Scan(&trackID); err != nil {
+			return fmt.Errorf("database: reorder playlist track: no track at position %d: %w", fromPos, err)
+		}
+
+		// Move the mover out of the way first, to position 0 (real positions
+		// start at 1), so the shift below never collides with it.
+		if _, err := tx.Exec(`UPDATE playlist_tracks SET position = 0 WHERE playlist_id = ? AND position = ?`, playlistID, fromPos); err != nil {
+			return err
+		}
+
+		// SQLite checks the UNIQUE constraint row by row, so the rows in
+		// between are shifted into negative position space first and then
+		// flipped back, rather than renumbered in place.
+		if fromPos < toPos {
+			if _, err := tx.Exec(`
+				UPDATE playlist_tracks SET position = -(position - 1)
+				WHERE playlist_id = ? AND position > ? AND position <= ?`, playlistID, fromPos, toPos); err != nil {
+				return err
+			}
+		} else {
+			if _, err := tx.Exec(`
+				UPDATE playlist_tracks SET position = -(position + 1)
+				WHERE playlist_id = ? AND position >= ? AND position < ?`, playlistID, toPos, fromPos); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(`UPDATE playlist_tracks SET position = -position WHERE playlist_id = ? AND position < 0`, playlistID); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`UPDATE playlist_tracks SET position = ? WHERE playlist_id = ? AND position = 0`, toPos, playlistID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// MovePlaylistTrackByPersistentID resolves playlistPersistentID and
+// trackPersistentID to their internal IDs and moves that track to toPos
+// within the playlist, via ReorderPlaylistTrack.
+func (m *DatabaseManager) MovePlaylistTrackByPersistentID(playlistPersistentID, trackPersistentID string, toPos int) error {
+	playlist, err := m.GetPlaylistByPersistentID(playlistPersistentID)
+	if err != nil {
+		return err
+	}
+	if playlist.Smart {
+		return fmt.Errorf("database: move playlist track %q: %w", playlistPersistentID, ErrSmartPlaylist)
+	}
+	var fromPos int
+	err = m.DB.QueryRow(`
+		SELECT pt.position FROM playlist_tracks pt
+		JOIN tracks t ON t.id = pt.track_id
+		WHERE pt.playlist_id = ? AND t.persistent_id = ?`, playlist.ID, trackPersistentID).Scan(&fromPos)
+	if err != nil {
+		return fmt.Errorf("database: move playlist track: track %q not in playlist: %w", trackPersistentID, err)
+	}
+	return m.ReorderPlaylistTrack(playlist.ID, fromPos, toPos)
+}
+
+// ReconcilePlaylistCounts recomputes every playlist's denormalized
+// track_count from the playlist_tracks junction table in one statement.
+// SyncPlaylist keeps the count in sync on its own, but this is a cheap
+// belt-and-suspenders pass for migrations and anything that touches
+// playlist_tracks directly.
+func (m *DatabaseManager) ReconcilePlaylistCounts() error {
+	return m.enqueueWrite(func() error {
+		_, err := m.DB.Exec(`
+			UPDATE playlists SET track_count = (
+				SELECT COUNT(*) FROM playlist_tracks WHERE playlist_tracks.playlist_id = playlists.id
+			)`)
+		if err != nil {
+			return fmt.Errorf("database: reconcile playlist counts: %w", err)
+		}
+		return nil
+	})
+}
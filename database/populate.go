@@ -0,0 +1,390 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// lastRefreshSkippedMetaKey stores the skipped-track count from the most
+// recent PopulateFromRefreshScript run, so it can be surfaced later (e.g.
+// by the validate command) without re-running a refresh.
+const lastRefreshSkippedMetaKey = "last_refresh_skipped_tracks"
+
+// RefreshTrack is a single track entry in a library-scan script's output.
+type RefreshTrack struct {
+	PersistentID string  `json:"persistent_id"`
+	Name         string  `json:"name"`
+	Artist       string  `json:"artist"`
+	Album        string  `json:"album"`
+	AlbumArtist  string  `json:"album_artist"`
+	Compilation  bool    `json:"compilation"`
+	Genre        string  `json:"genre"`
+	Duration     float64 `json:"duration"`
+	TrackKind    string  `json:"kind"`
+	Rating       int     `json:"rating"`
+	Starred      bool    `json:"starred"`
+	Disliked     bool    `json:"disliked"`
+	PlayCount    int     `json:"play_count"`
+	IsStreaming  bool    `json:"is_streaming"`
+	StreamURL    string  `json:"stream_url"`
+	Downloaded   bool    `json:"downloaded"`
+	// DateAdded is when Apple Music recorded the track as added to the
+	// library. Zero means the scan didn't report it (e.g. an older script),
+	// in which case insertOrUpdateRefreshTrack falls back to the current
+	// time on first insert and leaves an existing row's date_added alone.
+	DateAdded time.Time `json:"date_added"`
+}
+
+// RefreshPlaylist is a single playlist entry, with its member tracks by
+// persistent ID in order.
+type RefreshPlaylist struct {
+	PersistentID string   `json:"persistent_id"`
+	Name         string   `json:"name"`
+	SpecialKind  string   `json:"special_kind"`
+	ParentID     string   `json:"parent_id"`
+	Smart        bool     `json:"smart"`
+	TrackIDs     []string `json:"track_ids"`
+}
+
+// RefreshStats summarizes a single library-scan/migration run.
+type RefreshStats struct {
+	TrackCount     int           `json:"track_count"`
+	PlaylistCount  int           `json:"playlist_count"`
+	SkippedTracks  int           `json:"skipped_tracks"`
+	ProcessingTime time.Duration `json:"processing_time"`
+}
+
+// RefreshResponse is the normalized shape a library-scan script's output
+// (live JXA or a legacy cache file) is converted into before being applied
+// to the database.
+type RefreshResponse struct {
+	Tracks    []RefreshTrack    `json:"tracks"`
+	Playlists []RefreshPlaylist `json:"playlists"`
+	Stats     RefreshStats      `json:"stats"`
+}
+
+// PopulateOptions controls how PopulateFromRefreshScript (and its parallel
+// counterpart) reconcile playlist_tracks with a scan's reported membership.
+type PopulateOptions struct {
+	// MergePlaylists, when true, keeps each playlist's existing track
+	// associations and only adds the ones the scan reports, instead of the
+	// default replace behavior (wipe playlist_tracks for the playlist, then
+	// re-add it from the scan). Replace is authoritative and correct for a
+	// full library scan; merge suits a partial/filtered run (e.g. an
+	// "Offline"-only scan) that shouldn't be treated as the complete truth
+	// for playlists it touched.
+	MergePlaylists bool
+}
+
+// PopulateFromRefreshScript upserts artists/albums/genres/tracks and
+// playlists from resp, returning the resulting stats including how many
+// tracks were skipped (e.g. missing/invalid fields) so callers such as the
+// MCP refresh_library tool and itunes-migrate can report it to the user.
+// Playlist membership is replaced (see PopulateOptions); use
+// PopulateFromRefreshScriptWithOptions to merge instead.
+func (m *DatabaseManager) PopulateFromRefreshScript(resp *RefreshResponse) (*RefreshStats, error) {
+	return m.PopulateFromRefreshScriptWithOptions(resp, PopulateOptions{})
+}
+
+// PopulateFromRefreshScriptWithOptions behaves like PopulateFromRefreshScript
+// but lets the caller opt into merging playlist membership instead of
+// replacing it; see PopulateOptions.
+func (m *DatabaseManager) PopulateFromRefreshScriptWithOptions(resp *RefreshResponse, opts PopulateOptions) (*RefreshStats, error) {
+	start := time.Now()
+	trackIDByPersistentID := make(map[string]int64, len(resp.Tracks))
+
+	for _, t := range resp.Tracks {
+		id, err := m.insertOrUpdateRefreshTrack(t)
+		if err != nil {
+			resp.Stats.SkippedTracks++
+			continue
+		}
+		trackIDByPersistentID[t.PersistentID] = id
+	}
+
+	playlistIDByPersistentID := make(map[string]int64, len(resp.Playlists))
+	for _, p := range resp.Playlists {
+		id, err := m.syncRefreshPlaylist(p, trackIDByPersistentID, opts.MergePlaylists)
+		if err != nil {
+			continue
+		}
+		playlistIDByPersistentID[p.PersistentID] = id
+	}
+	if err := m.linkRefreshPlaylistParents(resp.Playlists, playlistIDByPersistentID); err != nil {
+		return nil, err
+	}
+	if err := m.ReconcilePlaylistCounts(); err != nil {
+		return nil, err
+	}
+	if err := m.ComputeRankings(); err != nil {
+		return nil, err
+	}
+
+	stats := resp.Stats
+	stats.TrackCount = len(resp.Tracks)
+	stats.PlaylistCount = len(resp.Playlists)
+	stats.ProcessingTime = time.Since(start)
+
+	if err := m.SetLastRefreshTime(time.Now()); err != nil {
+		return &stats, err
+	}
+	if err := m.SetMeta(lastRefreshSkippedMetaKey, strconv.Itoa(stats.SkippedTracks)); err != nil {
+		return &stats, err
+	}
+	return &stats, nil
+}
+
+// SyncPlaylistsFromRefresh reconciles playlists/playlist_tracks from
+// playlists without touching track metadata, for a lighter "playlists
+// only" refresh that skips the full track scan RefreshPlaylistsOnly exists
+// to avoid. Each playlist's TrackIDs are resolved against tracks already
+// cached from a prior full refresh; a persistent ID not yet known locally
+// is silently skipped, since the track will be picked up by the next full
+// refresh rather than being treated as an error here.
+func (m *DatabaseManager) SyncPlaylistsFromRefresh(playlists []RefreshPlaylist) (*RefreshStats, error) {
+	start := time.Now()
+
+	trackIDByPersistentID, err := m.trackIDsByPersistentID()
+	if err != nil {
+		return nil, err
+	}
+
+	playlistIDByPersistentID := make(map[string]int64, len(playlists))
+	for _, p := range playlists {
+		id, err := m.syncRefreshPlaylist(p, trackIDByPersistentID, false)
+		if err != nil {
+			continue
+		}
+		playlistIDByPersistentID[p.PersistentID] = id
+	}
+	if err := m.linkRefreshPlaylistParents(playlists, playlistIDByPersistentID); err != nil {
+		return nil, err
+	}
+	if err := m.ReconcilePlaylistCounts(); err != nil {
+		return nil, err
+	}
+
+	return &RefreshStats{
+		PlaylistCount:  len(playlists),
+		ProcessingTime: time.Since(start),
+	}, nil
+}
+
+// trackIDsByPersistentID maps every cached track's persistent ID to its
+// internal row ID, for resolving a playlist-only refresh's track_ids
+// without touching the tracks table.
+func (m *DatabaseManager) trackIDsByPersistentID() (map[string]int64, error) {
+	rows, err := m.DB.Query(`SELECT persistent_id, id FROM tracks`)
+	if err != nil {
+		return nil, fmt.Errorf("database: track IDs by persistent ID: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int64)
+	for rows.Next() {
+		var pid string
+		var id int64
+		if err := rows.Scan(&pid, &id); err != nil {
+			return nil, err
+		}
+		ids[pid] = id
+	}
+	return ids, rows.Err()
+}
+
+// GetLastRefreshSkippedTracks returns the skipped-track count recorded by
+// the most recent PopulateFromRefreshScript run, or 0 if none has run yet.
+func (m *DatabaseManager) GetLastRefreshSkippedTracks() (int, error) {
+	raw, ok, err := m.GetMeta(lastRefreshSkippedMetaKey)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return strconv.Atoi(raw)
+}
+
+func (m *DatabaseManager) insertOrUpdateRefreshTrack(t RefreshTrack) (int64, error) {
+	var id int64
+	err := m.enqueueWrite(func() error {
+		artistID, err := m.getOrCreateNamed("artists", t.Artist)
+		if err != nil {
+			return err
+		}
+
+		// Compilations (various-artists albums) are keyed on the album artist
+		// rather than each track's own artist, so every track lands on the
+		// same album row instead of fragmenting into one album per artist.
+		albumArtistID := artistID
+		if t.Compilation && t.AlbumArtist != "" {
+			albumArtistID, err = m.getOrCreateNamed("artists", t.AlbumArtist)
+			if err != nil {
+				return err
+			}
+		}
+		albumID, err := m.getOrCreateAlbum(t.Album, albumArtistID, t.Compilation)
+		if err != nil {
+			return err
+		}
+		genreID, err := m.getOrCreateNamed("genres", t.Genre)
+		if err != nil {
+			return err
+		}
+
+		dateAdded := t.DateAdded
+		if dateAdded.IsZero() {
+			dateAdded = time.Now()
+		}
+		query := `
+			INSERT INTO tracks (persistent_id, name, artist_id, album_id, genre_id, duration, track_kind, rating, starred, disliked, play_count, is_streaming, stream_url, downloaded, date_added)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(persistent_id) DO UPDATE SET
+				name = excluded.name, artist_id = excluded.artist_id, album_id = excluded.album_id,
+				genre_id = excluded.genre_id, duration = excluded.duration, track_kind = excluded.track_kind,
+				rating = excluded.rating, starred = excluded.starred, disliked = excluded.disliked, play_count = excluded.play_count,
+				is_streaming = excluded.is_streaming, stream_url = excluded.stream_url, downloaded = excluded.downloaded`
+		// date_added reflects when Apple Music actually added the track, not
+		// when this local row happened to be upserted, so an existing row keeps
+		// its date_added unless the scan reported a real value for it.
+		if !t.DateAdded.IsZero() {
+			query += `, date_added = excluded.date_added`
+		}
+		res, err := m.DB.Exec(query,
+			t.PersistentID, t.Name, artistID, albumID, genreID, t.Duration, t.TrackKind, t.Rating, t.Starred, t.Disliked, t.PlayCount, t.IsStreaming, t.StreamURL, t.Downloaded, dateAdded)
+		if err != nil {
+			return err
+		}
+		insertedID, err := res.LastInsertId()
+		if err != nil || insertedID == 0 {
+			var existingID int64
+			if qErr := m.DB.QueryRow(`SELECT id FROM tracks WHERE persistent_id = ?`, t.PersistentID).Scan(&existingID); qErr != nil {
+				return qErr
+			}
+			id = existingID
+			return nil
+		}
+		id = insertedID
+		return nil
+	})
+	return id, err
+}
+
+func (m *DatabaseManager) getOrCreateNamed(table, name string) (int64, error) {
+	if name == "" {
+		name = "Unknown"
+	}
+	var id int64
+	err := m.DB.QueryRow(`SELECT id FROM `+table+` WHERE name = ?`, name).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	res, err := m.DB.Exec(`INSERT INTO `+table+` (name) VALUES (?)`, name)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// getOrCreateAlbum resolves (or creates) the album row keyed on
+// (name, artistID), where artistID is the track's own artist for a normal
+// album or the resolved album artist for a compilation.
+func (m *DatabaseManager) getOrCreateAlbum(name string, artistID int64, compilation bool) (int64, error) {
+	if name == "" {
+		name = "Unknown"
+	}
+	var id int64
+	err := m.DB.QueryRow(`SELECT id FROM albums WHERE name = ? AND artist_id = ?`, name, artistID).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	res, err := m.DB.Exec(`INSERT INTO albums (name, artist_id, compilation) VALUES (?, ?, ?)`, name, artistID, compilation)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// syncRefreshPlaylist upserts p's playlist row and reconciles its
+// playlist_tracks. merge=false (the default) replaces membership outright
+// via SyncPlaylist; merge=true keeps existing associations and only adds
+// p's tracks via BatchInsertPlaylistTracks, which is safe to call with
+// tracks already on the playlist since (playlist_id, track_id) is the
+// table's primary key.
+func (m *DatabaseManager) syncRefreshPlaylist(p RefreshPlaylist, trackIDByPersistentID map[string]int64, merge bool) (int64, error) {
+	// Only the playlist upsert itself runs inside enqueueWrite: the
+	// BatchInsertPlaylistTracks/SyncPlaylist calls below enqueue their own
+	// writes, and nesting an enqueueWrite call inside another's closure
+	// would deadlock the single write-queue worker.
+	var playlistID int64
+	err := m.enqueueWrite(func() error {
+		res, err := m.DB.Exec(`
+			INSERT INTO playlists (persistent_id, name, special_kind, track_count, smart)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(persistent_id) DO UPDATE SET name = excluded.name, special_kind = excluded.special_kind, smart = excluded.smart`,
+			p.PersistentID, p.Name, orDefault(p.SpecialKind, "none"), len(p.TrackIDs), p.Smart)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil || id == 0 {
+			if qErr := m.DB.QueryRow(`SELECT id FROM playlists WHERE persistent_id = ?`, p.PersistentID).Scan(&id); qErr != nil {
+				return qErr
+			}
+		}
+		playlistID = id
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var trackIDs []int64
+	for _, pid := range p.TrackIDs {
+		if id, ok := trackIDByPersistentID[pid]; ok {
+			trackIDs = append(trackIDs, id)
+		}
+	}
+	if merge {
+		if err := m.BatchInsertPlaylistTracks(playlistID, trackIDs); err != nil {
+			return 0, err
+		}
+		return playlistID, nil
+	}
+	if _, err := m.SyncPlaylist(playlistID, trackIDs, false); err != nil {
+		return 0, err
+	}
+	return playlistID, nil
+}
+
+// linkRefreshPlaylistParents sets parent_id on every playlist whose
+// RefreshPlaylist.ParentID names another playlist in the same batch. It
+// runs as a pass separate from syncRefreshPlaylist because a folder isn't
+// guaranteed to appear before its children in resp.Playlists, so the full
+// persistent-ID-to-row-ID map has to exist before any linking can happen.
+func (m *DatabaseManager) linkRefreshPlaylistParents(playlists []RefreshPlaylist, playlistIDByPersistentID map[string]int64) error {
+	return m.enqueueWrite(func() error {
+		for _, p := range playlists {
+			if p.ParentID == "" {
+				continue
+			}
+			parentID, ok := playlistIDByPersistentID[p.ParentID]
+			if !ok {
+				continue
+			}
+			childID, ok := playlistIDByPersistentID[p.PersistentID]
+			if !ok {
+				continue
+			}
+			if _, err := m.DB.Exec(`UPDATE playlists SET parent_id = ? WHERE id = ?`, parentID, childID); err != nil {
+				return fmt.Errorf("database: link playlist parent: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,269 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultPopulateWorkers is the worker count
+// PopulateFromRefreshScriptParallel uses when the caller passes workers <= 0.
+const DefaultPopulateWorkers = 8
+
+// nameLookupRequest asks the single writer goroutine in resolveNamesParallel
+// to create a not-yet-seen name and report back its ID.
+type nameLookupRequest struct {
+	table string
+	name  string
+	reply chan nameLookupResult
+}
+
+type nameLookupResult struct {
+	name string
+	id   int64
+	err  error
+}
+
+// PopulateFromRefreshScriptParallel behaves like PopulateFromRefreshScript,
+// but resolves the distinct artist and genre names up front with a worker
+// pool of concurrent readers, instead of resolving them one track at a
+// time in PopulateFromRefreshScript's sequential loop. Every lookup
+// (SELECT) can run concurrently; every write (INSERT of a name not yet in
+// the table) is funneled through one goroutine, so SQLite only ever sees a
+// single writer at a time for those tables. Track insertion itself stays
+// sequential, since it's a single INSERT ... ON CONFLICT per track and
+// gains little from parallelizing while adding real risk of write
+// contention.
+//
+// workers <= 0 uses DefaultPopulateWorkers.
+func (m *DatabaseManager) PopulateFromRefreshScriptParallel(resp *RefreshResponse, workers int) (*RefreshStats, error) {
+	return m.PopulateFromRefreshScriptParallelWithOptions(resp, workers, PopulateOptions{})
+}
+
+// PopulateFromRefreshScriptParallelWithOptions behaves like
+// PopulateFromRefreshScriptParallel but lets the caller opt into merging
+// playlist membership instead of replacing it; see PopulateOptions.
+func (m *DatabaseManager) PopulateFromRefreshScriptParallelWithOptions(resp *RefreshResponse, workers int, opts PopulateOptions) (*RefreshStats, error) {
+	if workers <= 0 {
+		workers = DefaultPopulateWorkers
+	}
+	start := time.Now()
+
+	artistNames := make(map[string]struct{}, len(resp.Tracks))
+	genreNames := make(map[string]struct{}, len(resp.Tracks))
+	for _, t := range resp.Tracks {
+		artistNames[t.Artist] = struct{}{}
+		if t.Compilation && t.AlbumArtist != "" {
+			artistNames[t.AlbumArtist] = struct{}{}
+		}
+		genreNames[t.Genre] = struct{}{}
+	}
+
+	artistIDs, err := m.resolveNamesParallel("artists", mapKeys(artistNames), workers)
+	if err != nil {
+		return nil, err
+	}
+	genreIDs, err := m.resolveNamesParallel("genres", mapKeys(genreNames), workers)
+	if err != nil {
+		return nil, err
+	}
+
+	trackIDByPersistentID := make(map[string]int64, len(resp.Tracks))
+	albumIDCache := make(map[string]int64)
+	var albumMu sync.Mutex
+
+	for _, t := range resp.Tracks {
+		id, err := m.insertOrUpdateRefreshTrackCached(t, artistIDs, genreIDs, albumIDCache, &albumMu)
+		if err != nil {
+			resp.Stats.SkippedTracks++
+			continue
+		}
+		trackIDByPersistentID[t.PersistentID] = id
+	}
+
+	playlistIDByPersistentID := make(map[string]int64, len(resp.Playlists))
+	for _, p := range resp.Playlists {
+		id, err := m.syncRefreshPlaylist(p, trackIDByPersistentID, opts.MergePlaylists)
+		if err != nil {
+			continue
+		}
+		playlistIDByPersistentID[p.PersistentID] = id
+	}
+	if err := m.linkRefreshPlaylistParents(resp.Playlists, playlistIDByPersistentID); err != nil {
+		return nil, err
+	}
+	if err := m.ReconcilePlaylistCounts(); err != nil {
+		return nil, err
+	}
+	if err := m.ComputeRankings(); err != nil {
+		return nil, err
+	}
+
+	stats := resp.Stats
+	stats.TrackCount = len(resp.Tracks)
+	stats.PlaylistCount = len(resp.Playlists)
+	stats.ProcessingTime = time.Since(start)
+
+	if err := m.SetLastRefreshTime(time.Now()); err != nil {
+		return &stats, err
+	}
+	if err := m.SetMeta(lastRefreshSkippedMetaKey, strconv.Itoa(stats.SkippedTracks)); err != nil {
+		return &stats, err
+	}
+	return &stats, nil
+}
+
+// resolveNamesParallel resolves every name in names to its row ID in
+// table, reading concurrently across workers goroutines but routing every
+// INSERT of a not-yet-seen name through a single writer goroutine, so
+// concurrent readers can never race each other into creating duplicate
+// rows for the same name.
+func (m *DatabaseManager) resolveNamesParallel(table string, names []string, workers int) (map[string]int64, error) {
+	if len(names) == 0 {
+		return map[string]int64{}, nil
+	}
+	if workers > len(names) {
+		workers = len(names)
+	}
+
+	jobs := make(chan string)
+	results := make(chan nameLookupResult, len(names))
+	writes := make(chan nameLookupRequest)
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		for req := range writes {
+			id, err := m.getOrCreateNamed(req.table, req.name)
+			req.reply <- nameLookupResult{name: req.name, id: id, err: err}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				lookup := name
+				if lookup == "" {
+					lookup = "Unknown"
+				}
+				var id int64
+				err := m.DB.QueryRow(`SELECT id FROM `+table+` WHERE name = ?`, lookup).Scan(&id)
+				if err == nil {
+					results <- nameLookupResult{name: name, id: id}
+					continue
+				}
+				reply := make(chan nameLookupResult, 1)
+				writes <- nameLookupRequest{table: table, name: name, reply: reply}
+				results <- <-reply
+			}
+		}()
+	}
+
+	go func() {
+		for _, name := range names {
+			jobs <- name
+		}
+		close(jobs)
+	}()
+	go func() {
+		wg.Wait()
+		close(writes)
+	}()
+
+	out := make(map[string]int64, len(names))
+	var firstErr error
+	for i := 0; i < len(names); i++ {
+		r := <-results
+		if r.err != nil && firstErr == nil {
+			firstErr = r.err
+		}
+		out[r.name] = r.id
+	}
+	<-writerDone
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+// insertOrUpdateRefreshTrackCached is insertOrUpdateRefreshTrack's
+// counterpart for PopulateFromRefreshScriptParallel: artist and genre IDs
+// come from the pre-resolved maps instead of a per-track lookup, and album
+// IDs are resolved once per (name, artist) pair via albumIDCache, guarded
+// by albumMu since PopulateFromRefreshScriptParallel's track loop is
+// itself sequential but the cache is written under the same lock
+// discipline as the rest of this file's concurrent helpers.
+func (m *DatabaseManager) insertOrUpdateRefreshTrackCached(t RefreshTrack, artistIDs, genreIDs map[string]int64, albumIDCache map[string]int64, albumMu *sync.Mutex) (int64, error) {
+	artistID, ok := artistIDs[t.Artist]
+	if !ok {
+		return 0, fmt.Errorf("database: no resolved artist id for %q", t.Artist)
+	}
+	albumArtistID := artistID
+	if t.Compilation && t.AlbumArtist != "" {
+		id, ok := artistIDs[t.AlbumArtist]
+		if !ok {
+			return 0, fmt.Errorf("database: no resolved artist id for %q", t.AlbumArtist)
+		}
+		albumArtistID = id
+	}
+	genreID, ok := genreIDs[t.Genre]
+	if !ok {
+		return 0, fmt.Errorf("database: no resolved genre id for %q", t.Genre)
+	}
+
+	albumKey := strconv.FormatInt(albumArtistID, 10) + "|" + t.Album
+	albumMu.Lock()
+	albumID, ok := albumIDCache[albumKey]
+	if !ok {
+		var err error
+		albumID, err = m.getOrCreateAlbum(t.Album, albumArtistID, t.Compilation)
+		if err != nil {
+			albumMu.Unlock()
+			return 0, err
+		}
+		albumIDCache[albumKey] = albumID
+	}
+	albumMu.Unlock()
+
+	dateAdded := t.DateAdded
+	if dateAdded.IsZero() {
+		dateAdded = time.Now()
+	}
+	query := `
+		INSERT INTO tracks (persistent_id, name, artist_id, album_id, genre_id, duration, track_kind, rating, starred, disliked, play_count, is_streaming, stream_url, downloaded, date_added)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(persistent_id) DO UPDATE SET
+			name = excluded.name, artist_id = excluded.artist_id, album_id = excluded.album_id,
+			genre_id = excluded.genre_id, duration = excluded.duration, track_kind = excluded.track_kind,
+			rating = excluded.rating, starred = excluded.starred, disliked = excluded.disliked, play_count = excluded.play_count,
+			is_streaming = excluded.is_streaming, stream_url = excluded.stream_url, downloaded = excluded.downloaded`
+	if !t.DateAdded.IsZero() {
+		query += `, date_added = excluded.date_added`
+	}
+	res, err := m.DB.Exec(query,
+		t.PersistentID, t.Name, artistID, albumID, genreID, t.Duration, t.TrackKind, t.Rating, t.Starred, t.Disliked, t.PlayCount, t.IsStreaming, t.StreamURL, t.Downloaded, dateAdded)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil || id == 0 {
+		var existingID int64
+		if qErr := m.DB.QueryRow(`SELECT id FROM tracks WHERE persistent_id = ?`, t.PersistentID).Scan(&existingID); qErr != nil {
+			return 0, qErr
+		}
+		return existingID, nil
+	}
+	return id, nil
+}
+
+func mapKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+)
+
+func syntheticRefreshTracks(n int) []RefreshTrack {
+	tracks := make([]RefreshTrack, n)
+	for i := 0; i < n; i++ {
+		tracks[i] = RefreshTrack{
+			PersistentID: fmt.Sprintf("p%d", i),
+			Name:         fmt.Sprintf("Track %d", i),
+			Artist:       fmt.Sprintf("Artist %d", i%50),
+			Album:        fmt.Sprintf("Album %d", i%100),
+			Genre:        fmt.Sprintf("Genre %d", i%10),
+		}
+	}
+	return tracks
+}
+
+func TestPopulateFromRefreshScriptParallelMatchesSequential(t *testing.T) {
+	tracks := syntheticRefreshTracks(500)
+
+	seqDB := newTestDB(t)
+	if _, err := seqDB.PopulateFromRefreshScript(&RefreshResponse{Tracks: tracks}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	parDB := newTestDB(t)
+	stats, err := parDB.PopulateFromRefreshScriptParallel(&RefreshResponse{Tracks: tracks}, 4)
+	if err != nil {
+		t.Fatalf("PopulateFromRefreshScriptParallel: %v", err)
+	}
+	if stats.TrackCount != len(tracks) || stats.SkippedTracks != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	for _, table := range []string{"artists", "albums", "genres", "tracks"} {
+		var seqCount, parCount int
+		if err := seqDB.DB.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&seqCount); err != nil {
+			t.Fatalf("count %s (sequential): %v", table, err)
+		}
+		if err := parDB.DB.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&parCount); err != nil {
+			t.Fatalf("count %s (parallel): %v", table, err)
+		}
+		if seqCount != parCount {
+			t.Errorf("%s: sequential populate yielded %d rows, parallel yielded %d", table, seqCount, parCount)
+		}
+	}
+
+	var duplicateArtists int
+	if err := parDB.DB.QueryRow(`SELECT COUNT(*) - COUNT(DISTINCT name) FROM artists`).Scan(&duplicateArtists); err != nil {
+		t.Fatalf("check duplicate artists: %v", err)
+	}
+	if duplicateArtists != 0 {
+		t.Errorf("parallel populate created %d duplicate artist names", duplicateArtists)
+	}
+}
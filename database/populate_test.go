@@ -0,0 +1,34 @@
+package database
+
+import "testing"
+
+func TestPopulateFromRefreshScriptSurfacesSkippedTracks(t *testing.T) {
+	db := newTestDB(t)
+
+	resp := &RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "Song One", Artist: "Artist"},
+			{PersistentID: "p2", Name: "Song Two", Artist: "Artist"},
+		},
+		Stats: RefreshStats{SkippedTracks: 3}, // e.g. DRM/cloud-only items the script itself skipped
+	}
+
+	stats, err := db.PopulateFromRefreshScript(resp)
+	if err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	if stats.SkippedTracks != 3 {
+		t.Fatalf("SkippedTracks = %d, want 3", stats.SkippedTracks)
+	}
+	if stats.TrackCount != 2 {
+		t.Fatalf("TrackCount = %d, want 2", stats.TrackCount)
+	}
+
+	skipped, err := db.GetLastRefreshSkippedTracks()
+	if err != nil {
+		t.Fatalf("GetLastRefreshSkippedTracks: %v", err)
+	}
+	if skipped != 3 {
+		t.Fatalf("GetLastRefreshSkippedTracks() = %d, want 3", skipped)
+	}
+}
@@ -0,0 +1,30 @@
+package database
+
+import "fmt"
+
+// ComputeRankings derives a persistent tracks.ranking from rating,
+// play_count, and recency, so idx_tracks_ranking and every
+// "ORDER BY t.ranking" in search.go reflect something other than the
+// schema's 0.0 default. It runs as a single bulk UPDATE rather than a
+// per-track Go loop, since a full-library recompute genuinely needs every
+// row and SQLite does that far faster than a scan-and-rewrite from Go.
+//
+// The weighting mirrors relevanceScore's spirit for text matches: rating
+// dominates (the listener's own explicit signal), play_count contributes a
+// secondary signal capped at 100 plays so a handful of tracks played
+// thousands of times can't swamp everything else, and recency (time since
+// last played, falling back to date_added for never-played tracks) decays
+// smoothly on a roughly one-year half-life rather than stepping.
+func (m *DatabaseManager) ComputeRankings() error {
+	return m.enqueueWrite(func() error {
+		_, err := m.DB.Exec(`
+			UPDATE tracks SET ranking =
+				(CAST(rating AS REAL) / 100.0) * 0.5 +
+				(CAST(MIN(play_count, 100) AS REAL) / 100.0) * 0.3 +
+				(1.0 / (1.0 + (julianday('now') - julianday(COALESCE(last_played, date_added))) / 365.0)) * 0.2`)
+		if err != nil {
+			return fmt.Errorf("database: compute rankings: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,35 @@
+package database
+
+import "testing"
+
+// TestPopulateFromRefreshScriptComputesRankings asserts that a migration
+// leaves tracks.ranking populated (not the schema's 0.0 default) and
+// ordered sensibly: a highly rated, frequently played track should rank
+// above one with no rating or plays at all.
+func TestPopulateFromRefreshScriptComputesRankings(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p-favorite", Name: "Favorite", Artist: "Artist", Rating: 100, PlayCount: 50},
+			{PersistentID: "p-unknown", Name: "Unknown", Artist: "Artist"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	favorite, err := db.GetTrackByPersistentID("p-favorite")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID(favorite): %v", err)
+	}
+	unknown, err := db.GetTrackByPersistentID("p-unknown")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID(unknown): %v", err)
+	}
+
+	if favorite.Ranking <= 0 {
+		t.Fatalf("favorite.Ranking = %v, want > 0 after migration computes it", favorite.Ranking)
+	}
+	if favorite.Ranking <= unknown.Ranking {
+		t.Fatalf("favorite.Ranking = %v, want it to rank above unknown.Ranking = %v", favorite.Ranking, unknown.Ranking)
+	}
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrSchemaOutdated indicates a read-only DatabaseManager found the on-disk
+// schema missing a table or column InitSchema would otherwise add. A
+// read-only connection can't run migrations itself, so this is surfaced
+// clearly instead of the server silently running against a stale schema.
+var ErrSchemaOutdated = errors.New("database: schema is outdated, run itunes-migrate to update it")
+
+// NewDatabaseManagerReadOnly opens the SQLite database at path read-only
+// and never calls InitSchema, so it makes no write of any kind, even at
+// startup. This makes it safe to run alongside a concurrent itunes-migrate
+// process against the same file, which InitSchema's migrations (and the
+// write queue it otherwise starts) are not. An empty path is resolved via
+// ResolveDBPath, same as NewDatabaseManager. It still verifies the on-disk
+// schema is current, failing with ErrSchemaOutdated rather than running
+// against a database that hasn't been migrated yet.
+func NewDatabaseManagerReadOnly(path string) (*DatabaseManager, error) {
+	resolved, err := ResolveDBPath(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+resolved+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("database: open %q read-only: %w", resolved, err)
+	}
+	m := &DatabaseManager{DB: db, Path: resolved, readOnly: true}
+	if err := m.checkSchemaCurrent(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// checkSchemaCurrent verifies the tracks table exists and has every column
+// columnMigrations would otherwise add, without writing anything.
+func (m *DatabaseManager) checkSchemaCurrent() error {
+	var tableCount int
+	if err := m.DB.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'tracks'`).Scan(&tableCount); err != nil {
+		return fmt.Errorf("database: check schema: %w", err)
+	}
+	if tableCount == 0 {
+		return fmt.Errorf("%w: no tracks table found", ErrSchemaOutdated)
+	}
+	for _, step := range columnMigrations {
+		var count int
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM pragma_table_info('%s') WHERE name = ?`, step.table)
+		if err := m.DB.QueryRow(query, step.column).Scan(&count); err != nil {
+			return fmt.Errorf("database: check schema: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("%w: missing column %s.%s", ErrSchemaOutdated, step.table, step.column)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestNewDatabaseManagerReadOnlyRejectsWritesAllowsReads seeds a
+// file-backed database with a normal read-write manager, reopens it
+// read-only, and confirms reads still work while a write is rejected
+// outright rather than silently succeeding or hanging on the (never
+// started) write queue.
+func TestNewDatabaseManagerReadOnlyRejectsWritesAllowsReads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "readonly.db")
+
+	rw, err := NewDatabaseManager(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	if _, err := rw.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{{PersistentID: "p1", Name: "One", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("close read-write manager: %v", err)
+	}
+
+	ro, err := NewDatabaseManagerReadOnly(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseManagerReadOnly: %v", err)
+	}
+	defer ro.Close()
+
+	stats, err := ro.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats on read-only manager: %v", err)
+	}
+	if stats.TrackCount != 1 {
+		t.Fatalf("TrackCount = %d, want 1", stats.TrackCount)
+	}
+
+	if err := ro.SetTrackDisliked("p1", true); err == nil {
+		t.Fatal("SetTrackDisliked on read-only manager succeeded, want an error")
+	}
+}
+
+// TestNewDatabaseManagerReadOnlyFailsOnOutdatedSchema confirms a database
+// created before the downloaded column was added is rejected with
+// ErrSchemaOutdated instead of opening against a stale schema.
+func TestNewDatabaseManagerReadOnlyFailsOnOutdatedSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outdated.db")
+
+	rw, err := NewDatabaseManager(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	if _, err := rw.DB.Exec(`ALTER TABLE tracks DROP COLUMN downloaded`); err != nil {
+		t.Fatalf("drop downloaded column: %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("close read-write manager: %v", err)
+	}
+
+	_, err = NewDatabaseManagerReadOnly(path)
+	if !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("NewDatabaseManagerReadOnly error = %v, want ErrSchemaOutdated", err)
+	}
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetRecentlyAddedOrdering(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracks := []RefreshTrack{
+		{PersistentID: "p1", Name: "Oldest", Artist: "Artist A", DateAdded: now.AddDate(0, 0, -10)},
+		{PersistentID: "p2", Name: "Newest", Artist: "Artist A", DateAdded: now},
+		{PersistentID: "p3", Name: "Middle", Artist: "Artist B", DateAdded: now.AddDate(0, 0, -5)},
+	}
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{Tracks: tracks}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	recent, err := db.GetRecentlyAdded(2)
+	if err != nil {
+		t.Fatalf("GetRecentlyAdded: %v", err)
+	}
+	if len(recent) != 2 || recent[0].Name != "Newest" || recent[1].Name != "Middle" {
+		t.Fatalf("got %+v, want [Newest, Middle]", recent)
+	}
+}
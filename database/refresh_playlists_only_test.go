@@ -0,0 +1,57 @@
+package database
+
+import "testing"
+
+func TestSyncPlaylistsFromRefreshUpdatesMembershipOnly(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "t1", Name: "One", Artist: "Artist A", Rating: 60},
+			{PersistentID: "t2", Name: "Two", Artist: "Artist A", Rating: 80},
+		},
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "Mix", TrackIDs: []string{"t1"}},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	before, err := db.GetTrackByPersistentID("t1")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID(t1): %v", err)
+	}
+
+	stats, err := db.SyncPlaylistsFromRefresh([]RefreshPlaylist{
+		{PersistentID: "pl1", Name: "Mix", TrackIDs: []string{"t1", "t2"}},
+	})
+	if err != nil {
+		t.Fatalf("SyncPlaylistsFromRefresh: %v", err)
+	}
+	if stats.PlaylistCount != 1 {
+		t.Fatalf("PlaylistCount = %d, want 1", stats.PlaylistCount)
+	}
+
+	playlist, err := db.GetPlaylistByPersistentID("pl1")
+	if err != nil {
+		t.Fatalf("GetPlaylistByPersistentID: %v", err)
+	}
+	if playlist.TrackCount != 2 {
+		t.Fatalf("TrackCount = %d, want 2 after membership sync", playlist.TrackCount)
+	}
+
+	after, err := db.GetTrackByPersistentID("t1")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID(t1) after: %v", err)
+	}
+	if after.Name != before.Name || after.Artist != before.Artist || after.Rating != before.Rating {
+		t.Fatalf("track metadata changed: before=%+v after=%+v", before, after)
+	}
+
+	artists, err := db.GetTopArtists(10)
+	if err != nil {
+		t.Fatalf("GetTopArtists: %v", err)
+	}
+	if len(artists) != 1 || artists[0].Artist != "Artist A" {
+		t.Fatalf("got %+v, want a single unchanged Artist A entry", artists)
+	}
+}
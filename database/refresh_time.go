@@ -0,0 +1,88 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	lastRefreshMetaKey     = "last_refresh_time"
+	previousRefreshMetaKey = "previous_refresh_time"
+)
+
+// GetLastRefreshTime returns the timestamp of the last successful library
+// refresh, or the zero time if none has been recorded yet.
+func (m *DatabaseManager) GetLastRefreshTime() (time.Time, error) {
+	raw, ok, err := m.GetMeta(lastRefreshMetaKey)
+	if err != nil || !ok {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+// GetPreviousRefreshTime returns the timestamp of the refresh before the
+// last one, or the zero time if fewer than two refreshes have run. This is
+// what GetTracksAddedSinceLastRefresh bounds its query by, since by the
+// time it's called, GetLastRefreshTime already reflects the refresh that
+// just added the new tracks it's reporting on.
+func (m *DatabaseManager) GetPreviousRefreshTime() (time.Time, error) {
+	raw, ok, err := m.GetMeta(previousRefreshMetaKey)
+	if err != nil || !ok {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+// SetLastRefreshTime records t as the last successful library refresh
+// time, first saving whatever was previously recorded there as the
+// previous refresh time, so a "what's new" report run right after this
+// refresh can still see the boundary from before it.
+func (m *DatabaseManager) SetLastRefreshTime(t time.Time) error {
+	if prior, ok, err := m.GetMeta(lastRefreshMetaKey); err != nil {
+		return err
+	} else if ok {
+		if err := m.SetMeta(previousRefreshMetaKey, prior); err != nil {
+			return err
+		}
+	}
+	return m.SetMeta(lastRefreshMetaKey, t.Format(time.RFC3339))
+}
+
+// GetTracksAddedSinceLastRefresh returns tracks whose date_added falls
+// after GetPreviousRefreshTime, newest first, for a "what's new" changelog
+// covering the most recent refresh. Before a second refresh has ever run,
+// GetPreviousRefreshTime is the zero time, so this returns every track.
+func (m *DatabaseManager) GetTracksAddedSinceLastRefresh() ([]Track, error) {
+	since, err := m.GetPreviousRefreshTime()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.DB.Query(`
+		SELECT t.id, t.persistent_id, t.name,
+			COALESCE(ar.name, ''), COALESCE(al.name, ''), COALESCE(g.name, ''),
+			t.duration, t.track_kind, t.rating, t.starred, t.disliked, t.play_count,
+			t.last_played, t.date_added, t.ranking, t.is_streaming, t.stream_url, t.downloaded
+		FROM tracks t
+		LEFT JOIN artists ar ON ar.id = t.artist_id
+		LEFT JOIN albums al ON al.id = t.album_id
+		LEFT JOIN genres g ON g.id = t.genre_id
+		WHERE t.date_added > ?
+		ORDER BY t.date_added DESC`, since)
+	if err != nil {
+		return nil, fmt.Errorf("database: tracks added since last refresh: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []Track
+	for rows.Next() {
+		var t Track
+		if err := rows.Scan(&t.ID, &t.PersistentID, &t.Name, &t.Artist, &t.Album, &t.Genre,
+			&t.Duration, &t.TrackKind, &t.Rating, &t.Starred, &t.Disliked, &t.PlayCount,
+			&t.LastPlayed, &t.DateAdded, &t.Ranking, &t.IsStreaming, &t.StreamURL, &t.Downloaded); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, t)
+	}
+	return tracks, rows.Err()
+}
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLastRefreshTimeRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+
+	zero, err := db.GetLastRefreshTime()
+	if err != nil {
+		t.Fatalf("GetLastRefreshTime: %v", err)
+	}
+	if !zero.IsZero() {
+		t.Fatalf("got %v, want zero time before any refresh recorded", zero)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	if err := db.SetLastRefreshTime(now); err != nil {
+		t.Fatalf("SetLastRefreshTime: %v", err)
+	}
+	got, err := db.GetLastRefreshTime()
+	if err != nil {
+		t.Fatalf("GetLastRefreshTime: %v", err)
+	}
+	if !got.Equal(now) {
+		t.Fatalf("got %v, want %v", got, now)
+	}
+}
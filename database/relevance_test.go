@@ -0,0 +1,24 @@
+package database
+
+import "testing"
+
+func TestRelevanceScore(t *testing.T) {
+	track := Track{Name: "Bohemian Rhapsody", Artist: "Queen", Album: "A Night at the Opera"}
+
+	cases := []struct {
+		query string
+		want  float64
+	}{
+		{"", 0},
+		{"Bohemian Rhapsody", 1.0},
+		{"bohemian", 0.8},
+		{"rhapsody", 0.6},
+		{"queen", 0.4},
+		{"nomatch", 0.2},
+	}
+	for _, c := range cases {
+		if got := relevanceScore(c.query, track); got != c.want {
+			t.Errorf("relevanceScore(%q) = %v, want %v", c.query, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// RemapTrackPersistentID changes a track's persistent ID from oldID to
+// newID. playlist_tracks keys off the track's internal id (not
+// persistent_id), as does every other column on the tracks row itself
+// (rating, starred, disliked, play_count, ...), and the tracks_fts index is
+// kept in sync by the tracks_au trigger on any UPDATE to tracks regardless
+// of which column changed — so none of that is actually at risk of being
+// orphaned by a persistent ID change. This exists for the rarer case where
+// Apple Music itself reassigns a track's persistent ID (e.g. after a
+// library rebuild) and a caller wants the track to keep resolving under
+// its new ID without losing track of which row it refers to.
+//
+// Returns ErrNoTracksFound if oldID doesn't match a track, or
+// ErrPersistentIDConflict if newID already belongs to a different track.
+func (m *DatabaseManager) RemapTrackPersistentID(oldID, newID string) error {
+	return m.enqueueWrite(func() error {
+		tx, err := m.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		var trackID int64
+		if err := tx.QueryRow(`SELECT id FROM tracks WHERE persistent_id = ?`, oldID).Scan(&trackID); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNoTracksFound
+			}
+			return fmt.Errorf("database: remap persistent id: %w", err)
+		}
+
+		var conflictCount int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM tracks WHERE persistent_id = ?`, newID).Scan(&conflictCount); err != nil {
+			return fmt.Errorf("database: remap persistent id: %w", err)
+		}
+		if conflictCount > 0 {
+			return ErrPersistentIDConflict
+		}
+
+		if _, err := tx.Exec(`UPDATE tracks SET persistent_id = ? WHERE id = ?`, newID, trackID); err != nil {
+			return fmt.Errorf("database: remap persistent id: %w", err)
+		}
+		return tx.Commit()
+	})
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRemapTrackPersistentIDPreservesAssociationsAndMetadata(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{{PersistentID: "t1", Name: "One", Rating: 80, Starred: true}},
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "Favorites", TrackIDs: []string{"t1"}},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	if err := db.RemapTrackPersistentID("t1", "t1-new"); err != nil {
+		t.Fatalf("RemapTrackPersistentID: %v", err)
+	}
+
+	track, err := db.GetTrackByPersistentID("t1-new")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID(new id): %v", err)
+	}
+	if track.Rating != 80 || !track.Starred {
+		t.Fatalf("got rating=%d starred=%v, want rating=80 starred=true", track.Rating, track.Starred)
+	}
+
+	if _, err := db.GetTrackByPersistentID("t1"); err == nil {
+		t.Fatal("GetTrackByPersistentID(old id): got nil error, want failure")
+	}
+
+	playlist, err := db.GetPlaylistByPersistentID("pl1")
+	if err != nil {
+		t.Fatalf("GetPlaylistByPersistentID: %v", err)
+	}
+	page, err := db.GetPlaylistTracksPage(playlist.ID, 0, 0)
+	if err != nil {
+		t.Fatalf("GetPlaylistTracksPage: %v", err)
+	}
+	if page.Total != 1 || len(page.Tracks) != 1 || page.Tracks[0].PersistentID != "t1-new" {
+		t.Fatalf("got %+v, want playlist still holding the remapped track", page)
+	}
+}
+
+func TestRemapTrackPersistentIDErrors(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "t1", Name: "One"},
+			{PersistentID: "t2", Name: "Two"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	if err := db.RemapTrackPersistentID("missing", "t1-new"); !errors.Is(err, ErrNoTracksFound) {
+		t.Fatalf("RemapTrackPersistentID(missing old id): got %v, want ErrNoTracksFound", err)
+	}
+	if err := db.RemapTrackPersistentID("t1", "t2"); !errors.Is(err, ErrPersistentIDConflict) {
+		t.Fatalf("RemapTrackPersistentID(conflicting new id): got %v, want ErrPersistentIDConflict", err)
+	}
+}
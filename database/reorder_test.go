@@ -0,0 +1,95 @@
+package database
+
+import "testing"
+
+func setUpReorderPlaylist(t *testing.T) (db *DatabaseManager, playlistID int64) {
+	t.Helper()
+	db = newTestDB(t)
+	tracks := make([]RefreshTrack, 5)
+	for i := range tracks {
+		tracks[i] = RefreshTrack{PersistentID: string(rune('a' + i)), Name: string(rune('A' + i))}
+	}
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks:    tracks,
+		Playlists: []RefreshPlaylist{{PersistentID: "pl1", Name: "Five"}},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	playlist, err := db.GetPlaylistByPersistentID("pl1")
+	if err != nil {
+		t.Fatalf("GetPlaylistByPersistentID: %v", err)
+	}
+	playlistID = playlist.ID
+
+	ids := make([]string, 5)
+	for i := range ids {
+		ids[i] = string(rune('a' + i))
+	}
+	if _, err := db.SetPlaylistTracksByPersistentID("pl1", ids); err != nil {
+		t.Fatalf("SetPlaylistTracksByPersistentID: %v", err)
+	}
+	return db, playlistID
+}
+
+func orderedNames(t *testing.T, db *DatabaseManager, playlistID int64) []string {
+	t.Helper()
+	tracks, err := db.GetPlaylistTracks(playlistID)
+	if err != nil {
+		t.Fatalf("GetPlaylistTracks: %v", err)
+	}
+	names := make([]string, len(tracks))
+	for i, tr := range tracks {
+		names[i] = tr.Name
+	}
+	return names
+}
+
+func TestReorderPlaylistTrackForward(t *testing.T) {
+	db, playlistID := setUpReorderPlaylist(t)
+
+	// A B C D E -> move A (pos 1) to pos 4 -> B C D A E
+	if err := db.ReorderPlaylistTrack(playlistID, 1, 4); err != nil {
+		t.Fatalf("ReorderPlaylistTrack: %v", err)
+	}
+	want := []string{"B", "C", "D", "A", "E"}
+	if got := orderedNames(t, db, playlistID); !equalStrings(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestReorderPlaylistTrackBackward(t *testing.T) {
+	db, playlistID := setUpReorderPlaylist(t)
+
+	// A B C D E -> move D (pos 4) to pos 1 -> D A B C E
+	if err := db.ReorderPlaylistTrack(playlistID, 4, 1); err != nil {
+		t.Fatalf("ReorderPlaylistTrack: %v", err)
+	}
+	want := []string{"D", "A", "B", "C", "E"}
+	if got := orderedNames(t, db, playlistID); !equalStrings(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestMovePlaylistTrackByPersistentID(t *testing.T) {
+	db, playlistID := setUpReorderPlaylist(t)
+
+	if err := db.MovePlaylistTrackByPersistentID("pl1", "a", 5); err != nil {
+		t.Fatalf("MovePlaylistTrackByPersistentID: %v", err)
+	}
+	want := []string{"B", "C", "D", "E", "A"}
+	if got := orderedNames(t, db, playlistID); !equalStrings(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/pathutil"
+)
+
+func TestResolveDBPathPrecedence(t *testing.T) {
+	t.Setenv("ITUNES_DB_PATH", "/env/path.db")
+
+	got, err := ResolveDBPath("/explicit/path.db")
+	if err != nil {
+		t.Fatalf("ResolveDBPath: %v", err)
+	}
+	if got != "/explicit/path.db" {
+		t.Fatalf("got %q, want explicit argument to win over env var", got)
+	}
+
+	got, err = ResolveDBPath("")
+	if err != nil {
+		t.Fatalf("ResolveDBPath: %v", err)
+	}
+	if got != "/env/path.db" {
+		t.Fatalf("got %q, want env var to win over default when no explicit path given", got)
+	}
+}
+
+// TestResolveDBPathUsesActiveProfileBeforeDefault confirms a profile set via
+// pathutil.SetActiveProfile is used when neither an explicit path nor
+// ITUNES_DB_PATH is given, so "itunes profiles use <name>" actually changes
+// which database plain "itunes search" etc. open.
+func TestResolveDBPathUsesActiveProfileBeforeDefault(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("ITUNES_CONFIG_DIR", configDir)
+	t.Setenv("HOME", t.TempDir())
+
+	profilePath := filepath.Join(configDir, "work.db")
+	if err := os.WriteFile(profilePath, nil, 0o644); err != nil {
+		t.Fatalf("seed profile db: %v", err)
+	}
+	if err := pathutil.SetActiveProfile("work"); err != nil {
+		t.Fatalf("SetActiveProfile: %v", err)
+	}
+
+	got, err := ResolveDBPath("")
+	if err != nil {
+		t.Fatalf("ResolveDBPath: %v", err)
+	}
+	if got != profilePath {
+		t.Fatalf("got %q, want active profile's db path %q", got, profilePath)
+	}
+}
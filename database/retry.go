@@ -0,0 +1,49 @@
+package database
+
+import (
+	"strings"
+	"time"
+)
+
+// maxLockRetries bounds how many times withRetry reattempts a write that
+// failed with "database is locked", so a genuinely stuck lock (e.g. another
+// process crashed mid-transaction) eventually surfaces as a real error
+// instead of retrying forever.
+const maxLockRetries = 5
+
+// lockRetryBaseDelay is the backoff withRetry waits before its first retry;
+// each subsequent attempt doubles it.
+const lockRetryBaseDelay = 20 * time.Millisecond
+
+// isDatabaseLocked reports whether err is SQLite's transient "database is
+// locked" (SQLITE_BUSY) error, as opposed to a real failure retrying can't
+// fix.
+func isDatabaseLocked(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "SQLITE_BUSY")
+}
+
+// withRetry runs fn, retrying with exponential backoff when it fails with
+// SQLite's transient "database is locked" error. This lets the MCP server
+// and the migrate tool write to the same database concurrently without one
+// side's write failing outright just because the other held the lock for a
+// few milliseconds.
+func withRetry(fn func() error) error {
+	delay := lockRetryBaseDelay
+	var err error
+	for attempt := 0; attempt <= maxLockRetries; attempt++ {
+		err = fn()
+		if err == nil || !isDatabaseLocked(err) {
+			return err
+		}
+		if attempt == maxLockRetries {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestIsDatabaseLocked(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{fmt.Errorf("database is locked (5) (SQLITE_BUSY)"), true},
+		{fmt.Errorf("some other failure"), false},
+	}
+	for _, c := range cases {
+		if got := isDatabaseLocked(c.err); got != c.want {
+			t.Errorf("isDatabaseLocked(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestWithRetryRecoversFromTransientLock(t *testing.T) {
+	attempts := 0
+	err := withRetry(func() error {
+		attempts++
+		if attempts < 3 {
+			return fmt.Errorf("database is locked")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withRetry: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("got %d attempts, want 3", attempts)
+	}
+}
+
+func TestWithRetryDoesNotRetryRealErrors(t *testing.T) {
+	attempts := 0
+	wantErr := fmt.Errorf("boom")
+	err := withRetry(func() error {
+		attempts++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("got %v, want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Fatalf("got %d attempts, want 1 (no retry for non-lock errors)", attempts)
+	}
+}
+
+// TestConcurrentManagersEventuallySucceed simulates the MCP server and the
+// migrate tool writing radio stations to the same on-disk database at the
+// same time, confirming withRetry lets both sides' writes land instead of
+// one failing outright on a transient lock.
+func TestConcurrentManagersEventuallySucceed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contention.db")
+
+	a, err := NewDatabaseManager(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseManager (a): %v", err)
+	}
+	t.Cleanup(func() { a.Close() })
+
+	b, err := NewDatabaseManager(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseManager (b): %v", err)
+	}
+	t.Cleanup(func() { b.Close() })
+
+	const perManager = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*perManager)
+	write := func(db *DatabaseManager, prefix string) {
+		defer wg.Done()
+		for i := 0; i < perManager; i++ {
+			station := &RadioStation{
+				Name: fmt.Sprintf("%s-%d", prefix, i),
+				URL:  fmt.Sprintf("https://example.com/%s/%d", prefix, i),
+			}
+			if err := db.AddRadioStation(station); err != nil {
+				errs <- err
+			}
+		}
+	}
+
+	wg.Add(2)
+	go write(a, "a")
+	go write(b, "b")
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("AddRadioStation failed under contention: %v", err)
+	}
+
+	stations, err := a.ListRadioStations(nil)
+	if err != nil {
+		t.Fatalf("ListRadioStations: %v", err)
+	}
+	if len(stations) != 2*perManager {
+		t.Fatalf("got %d stations, want %d", len(stations), 2*perManager)
+	}
+}
@@ -0,0 +1,191 @@
+package database
+
+import "fmt"
+
+// SchemaVersion identifies the current shape of the on-disk database so
+// callers can detect when a migration is required.
+const SchemaVersion = 7
+
+// baseSchema creates the core tables used by the library cache: artists,
+// albums, genres, tracks, playlists, their junction table, radio stations,
+// and the tracks full-text index. It is safe to run repeatedly.
+const baseSchema = `
+CREATE TABLE IF NOT EXISTS artists (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS genres (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS albums (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	artist_id INTEGER NOT NULL REFERENCES artists(id),
+	compilation BOOLEAN NOT NULL DEFAULT 0,
+	UNIQUE(name, artist_id)
+);
+
+CREATE TABLE IF NOT EXISTS tracks (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	persistent_id TEXT NOT NULL UNIQUE,
+	name TEXT NOT NULL,
+	artist_id INTEGER REFERENCES artists(id),
+	album_id INTEGER REFERENCES albums(id),
+	genre_id INTEGER REFERENCES genres(id),
+	duration REAL NOT NULL DEFAULT 0,
+	track_kind TEXT NOT NULL DEFAULT '',
+	rating INTEGER NOT NULL DEFAULT 0,
+	starred BOOLEAN NOT NULL DEFAULT 0,
+	disliked BOOLEAN NOT NULL DEFAULT 0,
+	play_count INTEGER NOT NULL DEFAULT 0,
+	last_played DATETIME,
+	date_added DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	ranking REAL NOT NULL DEFAULT 0.0,
+	is_streaming BOOLEAN NOT NULL DEFAULT 0,
+	stream_url TEXT NOT NULL DEFAULT '',
+	downloaded BOOLEAN NOT NULL DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_tracks_date_added ON tracks(date_added);
+CREATE INDEX IF NOT EXISTS idx_tracks_ranking ON tracks(ranking);
+CREATE INDEX IF NOT EXISTS idx_tracks_rating ON tracks(rating);
+
+CREATE TABLE IF NOT EXISTS playlists (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	persistent_id TEXT NOT NULL UNIQUE,
+	name TEXT NOT NULL,
+	special_kind TEXT NOT NULL DEFAULT 'none',
+	track_count INTEGER NOT NULL DEFAULT 0,
+	parent_id INTEGER REFERENCES playlists(id),
+	smart BOOLEAN NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS playlist_tracks (
+	playlist_id INTEGER NOT NULL REFERENCES playlists(id),
+	track_id INTEGER NOT NULL REFERENCES tracks(id),
+	position INTEGER NOT NULL,
+	PRIMARY KEY (playlist_id, track_id),
+	UNIQUE (playlist_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS radio_stations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	url TEXT NOT NULL UNIQUE,
+	description TEXT NOT NULL DEFAULT '',
+	genre_id INTEGER REFERENCES genres(id),
+	homepage TEXT NOT NULL DEFAULT '',
+	is_active BOOLEAN NOT NULL DEFAULT 1,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS metadata (
+	key TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS search_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	query TEXT NOT NULL,
+	filters TEXT NOT NULL DEFAULT '',
+	result_count INTEGER NOT NULL DEFAULT 0,
+	searched_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_search_log_query ON search_log(query);
+
+CREATE TABLE IF NOT EXISTS search_cache (
+	cache_key TEXT PRIMARY KEY,
+	results_json TEXT NOT NULL,
+	cached_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS tracks_fts USING fts5(
+	name, artist, album, genre,
+	content='tracks',
+	content_rowid='id',
+	tokenize = "unicode61 remove_diacritics 2"
+);
+
+CREATE TRIGGER IF NOT EXISTS tracks_ai AFTER INSERT ON tracks BEGIN
+	INSERT INTO tracks_fts(rowid, name, artist, album, genre)
+	SELECT new.id, new.name,
+		(SELECT name FROM artists WHERE id = new.artist_id),
+		(SELECT name FROM albums WHERE id = new.album_id),
+		(SELECT name FROM genres WHERE id = new.genre_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tracks_ad AFTER DELETE ON tracks BEGIN
+	INSERT INTO tracks_fts(tracks_fts, rowid, name, artist, album, genre)
+	VALUES ('delete', old.id, old.name, '', '', '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS tracks_au AFTER UPDATE ON tracks BEGIN
+	INSERT INTO tracks_fts(tracks_fts, rowid, name, artist, album, genre)
+	VALUES ('delete', old.id, old.name, '', '', '');
+	INSERT INTO tracks_fts(rowid, name, artist, album, genre)
+	SELECT new.id, new.name,
+		(SELECT name FROM artists WHERE id = new.artist_id),
+		(SELECT name FROM albums WHERE id = new.album_id),
+		(SELECT name FROM genres WHERE id = new.genre_id);
+END;
+`
+
+// InitSchema creates the schema (tables, indices, FTS triggers) if it does
+// not already exist, then applies any column migrations needed to bring an
+// older on-disk database up to the current SchemaVersion. It is invoked
+// once by NewDatabaseManager.
+func (m *DatabaseManager) InitSchema() error {
+	// WAL lets readers proceed concurrently with the single writer (vs.
+	// SQLite's default rollback journal, which blocks readers for the
+	// duration of a write), which is what makes it safe for reads to
+	// bypass the write queue entirely.
+	if _, err := m.DB.Exec(`PRAGMA journal_mode = WAL`); err != nil {
+		return err
+	}
+	// busy_timeout makes SQLite itself wait (rather than immediately
+	// returning SQLITE_BUSY) when another connection holds the write lock,
+	// so withRetry's backoff only kicks in for contention that outlasts
+	// this window.
+	if _, err := m.DB.Exec(`PRAGMA busy_timeout = 5000`); err != nil {
+		return err
+	}
+	if _, err := m.DB.Exec(baseSchema); err != nil {
+		return err
+	}
+	return m.runColumnMigrations()
+}
+
+// columnMigrations lists columns added to baseSchema's tables after their
+// first release. CREATE TABLE IF NOT EXISTS only covers brand-new
+// databases, so older ones need an explicit ALTER TABLE for each.
+var columnMigrations = []struct {
+	table, column, ddl string
+}{
+	{"tracks", "disliked", "ALTER TABLE tracks ADD COLUMN disliked BOOLEAN NOT NULL DEFAULT 0"},
+	{"albums", "compilation", "ALTER TABLE albums ADD COLUMN compilation BOOLEAN NOT NULL DEFAULT 0"},
+	{"playlists", "parent_id", "ALTER TABLE playlists ADD COLUMN parent_id INTEGER REFERENCES playlists(id)"},
+	{"playlists", "smart", "ALTER TABLE playlists ADD COLUMN smart BOOLEAN NOT NULL DEFAULT 0"},
+	{"tracks", "downloaded", "ALTER TABLE tracks ADD COLUMN downloaded BOOLEAN NOT NULL DEFAULT 1"},
+}
+
+// runColumnMigrations applies any columnMigrations entries missing from the
+// current database. Each step is idempotent and safe to run on every
+// startup.
+func (m *DatabaseManager) runColumnMigrations() error {
+	for _, step := range columnMigrations {
+		var count int
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM pragma_table_info('%s') WHERE name = ?`, step.table)
+		if err := m.DB.QueryRow(query, step.column).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := m.DB.Exec(step.ddl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
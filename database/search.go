@@ -0,0 +1,571 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// DefaultSearchLimit caps the number of rows a text search returns when
+// neither a programmatic limit nor ITUNES_SEARCH_LIMIT overrides it.
+const DefaultSearchLimit = 50
+
+// DefaultMinQueryLength is the shortest non-empty query SearchTracks will
+// execute when neither a programmatic value nor ITUNES_MIN_QUERY_LENGTH
+// overrides it. 1 means every non-empty query is allowed, so behavior is
+// unchanged unless a caller raises it.
+const DefaultMinQueryLength = 1
+
+// ErrQueryTooShort is returned when a non-empty query is shorter than the
+// SearchManager's MinQueryLength. A short query against a large FTS index
+// can be slow and returns mostly noise, so this is surfaced as a distinct
+// error rather than silently running the scan. Filter-only searches (an
+// empty query with filters set) are never subject to this check.
+var ErrQueryTooShort = errors.New("database: query shorter than the configured minimum length")
+
+// SearchMetrics captures timing/diagnostic info for the most recent search,
+// useful for debugging slow queries.
+type SearchMetrics struct {
+	Duration time.Duration
+	Method   string // "fts" or "like"
+	CacheHit bool
+	TimedOut bool
+}
+
+// ErrSearchTimeout indicates a search's time budget (set via
+// SearchTracksWithTimeout) elapsed before the query finished.
+var ErrSearchTimeout = errors.New("database: search timed out")
+
+// SearchTimeoutError is returned by SearchTracksWithTimeout when the
+// query's context deadline elapses. Partial holds whatever rows had
+// already been scanned before the deadline hit, so a caller that doesn't
+// need every result can still use what arrived in time instead of
+// treating the search as a total failure.
+type SearchTimeoutError struct {
+	Partial []Track
+}
+
+func (e *SearchTimeoutError) Error() string {
+	return fmt.Sprintf("database: search timed out after %d partial result(s)", len(e.Partial))
+}
+
+func (e *SearchTimeoutError) Unwrap() error { return ErrSearchTimeout }
+
+// SearchManager executes track searches against the database, caching
+// recent results. Limit is set once at construction time rather than read
+// from a mutable package global, so it's safe to vary per-instance (e.g.
+// in tests) without racing concurrent searches.
+type SearchManager struct {
+	DB             *DatabaseManager
+	Limit          int
+	MinQueryLength int
+	// PersistCache, when true, backs the in-memory search cache with the
+	// on-disk search_cache table: every cache write goes through to disk,
+	// and NewSearchManagerWithLimit loads whatever hasn't expired back into
+	// memory at construction time, so a restarted process isn't cold on its
+	// first search. Off by default (ITUNES_PERSIST_SEARCH_CACHE), since it
+	// trades freshness for warmth.
+	PersistCache bool
+	// CacheTTL bounds how old a disk-persisted cache entry may be before
+	// LoadPersistedCache discards it instead of reviving it. Defaults to
+	// DefaultCacheTTL unless ITUNES_SEARCH_CACHE_TTL is set.
+	CacheTTL    time.Duration
+	lastMetrics SearchMetrics
+
+	cacheMu sync.RWMutex
+	cache   map[string][]Track
+}
+
+// NewSearchManager wraps a DatabaseManager for search use, with Limit
+// defaulting to DefaultSearchLimit unless ITUNES_SEARCH_LIMIT is set, and
+// MinQueryLength defaulting to DefaultMinQueryLength unless
+// ITUNES_MIN_QUERY_LENGTH is set.
+func NewSearchManager(db *DatabaseManager) *SearchManager {
+	return NewSearchManagerWithLimit(db, searchLimitFromEnv())
+}
+
+// NewSearchManagerWithLimit wraps a DatabaseManager for search use with an
+// explicit, programmatically-set result limit, bypassing ITUNES_SEARCH_LIMIT.
+// MinQueryLength, PersistCache, and CacheTTL still default from their
+// respective environment variables. When PersistCache ends up enabled, this
+// also loads whatever's already on disk via LoadPersistedCache, logging
+// (rather than failing construction on) any error doing so.
+func NewSearchManagerWithLimit(db *DatabaseManager, limit int) *SearchManager {
+	sm := &SearchManager{
+		DB:             db,
+		Limit:          limit,
+		MinQueryLength: minQueryLengthFromEnv(),
+		PersistCache:   persistCacheEnabledFromEnv(),
+		CacheTTL:       cacheTTLFromEnv(),
+		cache:          make(map[string][]Track),
+	}
+	if sm.PersistCache {
+		if err := sm.LoadPersistedCache(); err != nil {
+			slog.Warn("database: load persisted search cache", "error", err)
+		}
+	}
+	return sm
+}
+
+// ClearCache drops every cached search result, on disk too when
+// PersistCache is enabled. Callers that write to the tracks table
+// (rating/starred/disliked changes, a library refresh, playlist edits)
+// must call this afterward, or SearchTracks can keep serving a stale
+// result for a query it already answered. Invalidation is all-or-nothing
+// rather than per-query, since a write's effect on which queries it could
+// affect (e.g. a new disliked flag changing a filtered search) isn't cheap
+// to compute precisely.
+func (sm *SearchManager) ClearCache() {
+	sm.cacheMu.Lock()
+	sm.cache = make(map[string][]Track)
+	sm.cacheMu.Unlock()
+	if sm.PersistCache {
+		sm.clearPersistedCache()
+	}
+}
+
+// searchCacheKey builds a cache key that's sensitive to every input that
+// affects executeSearchQuery's result set.
+func searchCacheKey(query string, filters *SearchFilters, limit int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "q=%s&limit=%d", query, limit)
+	if filters != nil {
+		fmt.Fprintf(&b, "&artist=%s&artist_prefix=%s&album=%s&genre=%s&min_rating=%d&kind=%s", filters.Artist, filters.ArtistPrefix, filters.Album, filters.Genre, filters.MinRating, filters.Kind)
+		fmt.Fprintf(&b, "&starred=%v&disliked=%v&streaming_only=%v&local_only=%v&downloaded_only=%v&exact_match=%v&exact_name=%v&use_bm25=%s",
+			boolPtrString(filters.Starred), boolPtrString(filters.Disliked), boolPtrString(filters.StreamingOnly), boolPtrString(filters.LocalOnly), boolPtrString(filters.DownloadedOnly), filters.ExactMatch, filters.ExactName, boolPtrString(filters.UseBM25))
+	}
+	return b.String()
+}
+
+func boolPtrString(b *bool) string {
+	if b == nil {
+		return "nil"
+	}
+	return strconv.FormatBool(*b)
+}
+
+func searchLimitFromEnv() int {
+	if v := os.Getenv("ITUNES_SEARCH_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return DefaultSearchLimit
+}
+
+func minQueryLengthFromEnv() int {
+	if v := os.Getenv("ITUNES_MIN_QUERY_LENGTH"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return DefaultMinQueryLength
+}
+
+// LastMetrics returns the metrics recorded by the most recently executed
+// search.
+func (sm *SearchManager) LastMetrics() SearchMetrics {
+	return sm.lastMetrics
+}
+
+// SearchTracks runs a full-text (falling back to LIKE) search over tracks,
+// applying filters and capping at sm.Limit results. If ITUNES_SEARCH_LOG is
+// set, the query is also logged via LogSearch for later review through
+// GetPopularSearches; logging errors are swallowed so an analytics-write
+// failure never breaks a real search.
+func (sm *SearchManager) SearchTracks(query string, filters *SearchFilters) ([]Track, error) {
+	tracks, err := sm.executeSearchQuery(context.Background(), query, filters, sm.Limit)
+	if err == nil {
+		_ = sm.DB.LogSearch(query, filters, len(tracks))
+	}
+	return tracks, err
+}
+
+// SearchTracksWithTimeout is SearchTracks with a latency budget: if the
+// query hasn't finished within timeout, it's cancelled and a
+// *SearchTimeoutError is returned carrying whatever rows had already been
+// scanned, so a pathological query against a very large library can't hang
+// the caller (e.g. the MCP server) indefinitely.
+func (sm *SearchManager) SearchTracksWithTimeout(query string, filters *SearchFilters, timeout time.Duration) ([]Track, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	tracks, err := sm.executeSearchQuery(ctx, query, filters, sm.Limit)
+	if err == nil {
+		_ = sm.DB.LogSearch(query, filters, len(tracks))
+	}
+	return tracks, err
+}
+
+func (sm *SearchManager) executeSearchQuery(ctx context.Context, query string, filters *SearchFilters, limit int) ([]Track, error) {
+	if query != "" && len(query) < sm.MinQueryLength {
+		return nil, ErrQueryTooShort
+	}
+
+	start := time.Now()
+	method := "like"
+
+	key := searchCacheKey(query, filters, limit)
+	sm.cacheMu.RLock()
+	cached, hit := sm.cache[key]
+	sm.cacheMu.RUnlock()
+	if hit {
+		sm.lastMetrics = SearchMetrics{Duration: time.Since(start), Method: method, CacheHit: true}
+		slog.Debug("database: search cache hit", "query", query, "key", key)
+		return cached, nil
+	}
+
+	useBM25 := query != "" && (filters == nil || !filters.ExactMatch) && (filters == nil || !filters.ExactName) && (filters == nil || filters.UseBM25 == nil || *filters.UseBM25)
+	var ftsMatch string
+	if useBM25 {
+		ftsMatch = buildFTSMatchQuery(query)
+		useBM25 = ftsMatch != ""
+	}
+
+	var sqlQuery string
+	var args []interface{}
+	if useBM25 {
+		method = "fts"
+		sqlQuery = `
+			SELECT t.id, t.persistent_id, t.name,
+				COALESCE(ar.name, ''), COALESCE(al.name, ''), COALESCE(g.name, ''),
+				t.duration, t.track_kind, t.rating, t.starred, t.disliked, t.play_count,
+				t.last_played, t.date_added, t.ranking, t.is_streaming, t.stream_url, t.downloaded
+			FROM tracks_fts
+			JOIN tracks t ON t.id = tracks_fts.rowid
+			LEFT JOIN artists ar ON ar.id = t.artist_id
+			LEFT JOIN albums al ON al.id = t.album_id
+			LEFT JOIN genres g ON g.id = t.genre_id
+			WHERE tracks_fts MATCH ?`
+		args = append(args, ftsMatch)
+	} else {
+		sqlQuery = `
+			SELECT t.id, t.persistent_id, t.name,
+				COALESCE(ar.name, ''), COALESCE(al.name, ''), COALESCE(g.name, ''),
+				t.duration, t.track_kind, t.rating, t.starred, t.disliked, t.play_count,
+				t.last_played, t.date_added, t.ranking, t.is_streaming, t.stream_url, t.downloaded
+			FROM tracks t
+			LEFT JOIN artists ar ON ar.id = t.artist_id
+			LEFT JOIN albums al ON al.id = t.album_id
+			LEFT JOIN genres g ON g.id = t.genre_id
+			WHERE 1 = 1`
+	}
+
+	matchOp := "LIKE"
+	wildcard := "%"
+	if filters != nil && filters.ExactMatch {
+		matchOp = "GLOB"
+		wildcard = "*"
+	}
+
+	if query != "" && !useBM25 {
+		if filters != nil && filters.ExactName {
+			sqlQuery += ` AND t.name = ? COLLATE NOCASE`
+			args = append(args, query)
+		} else {
+			sqlQuery += fmt.Sprintf(` AND (t.name %s ? || ? || ? OR ar.name %s ? || ? || ? OR al.name %s ? || ? || ?)`, matchOp, matchOp, matchOp)
+			args = append(args, wildcard, query, wildcard, wildcard, query, wildcard, wildcard, query, wildcard)
+		}
+	}
+	sqlQuery, args = appendSearchFilters(sqlQuery, args, filters, matchOp, wildcard)
+	if useBM25 {
+		sqlQuery += ` ORDER BY bm25(tracks_fts) ASC, t.ranking DESC, t.name LIMIT ?`
+	} else {
+		sqlQuery += ` ORDER BY t.ranking DESC, t.name LIMIT ?`
+	}
+	args = append(args, limit)
+
+	slog.Debug("database: search query", "sql", sqlQuery, "args", args)
+
+	rows, err := sm.DB.DB.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		if ctx.Err() != nil {
+			sm.lastMetrics = SearchMetrics{Duration: time.Since(start), Method: method, TimedOut: true}
+			return nil, &SearchTimeoutError{}
+		}
+		return nil, fmt.Errorf("database: search tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var tracks []Track
+	for rows.Next() {
+		var t Track
+		if err := rows.Scan(&t.ID, &t.PersistentID, &t.Name, &t.Artist, &t.Album, &t.Genre,
+			&t.Duration, &t.TrackKind, &t.Rating, &t.Starred, &t.Disliked, &t.PlayCount,
+			&t.LastPlayed, &t.DateAdded, &t.Ranking, &t.IsStreaming, &t.StreamURL, &t.Downloaded); err != nil {
+			return nil, err
+		}
+		t.Relevance = relevanceScore(query, t)
+		tracks = append(tracks, t)
+	}
+	if err := rows.Err(); err != nil {
+		if ctx.Err() != nil {
+			sm.lastMetrics = SearchMetrics{Duration: time.Since(start), Method: method, TimedOut: true}
+			return tracks, &SearchTimeoutError{Partial: tracks}
+		}
+		return nil, err
+	}
+	sm.lastMetrics = SearchMetrics{Duration: time.Since(start), Method: method, CacheHit: false}
+	slog.Debug("database: search query complete", "query", query, "method", method, "results", len(tracks), "duration", sm.lastMetrics.Duration)
+
+	sm.cacheMu.Lock()
+	sm.cache[key] = tracks
+	sm.cacheMu.Unlock()
+	if sm.PersistCache {
+		sm.persistCacheEntry(key, tracks)
+	}
+
+	return tracks, nil
+}
+
+// appendSearchFilters appends filters' AND-clauses to sqlQuery and args,
+// using matchOp/wildcard for the Artist/Album substring filters (LIKE or,
+// with ExactMatch, GLOB). It does not touch the free-text query match,
+// which callers add themselves since it varies with the FTS-vs-LIKE path.
+// Shared by executeSearchQuery, SearchAlbums, and SearchArtists so the
+// three stay in lockstep on what a given SearchFilters means.
+func appendSearchFilters(sqlQuery string, args []interface{}, filters *SearchFilters, matchOp, wildcard string) (string, []interface{}) {
+	if filters == nil {
+		return sqlQuery, args
+	}
+	if filters.Artist != "" {
+		sqlQuery += fmt.Sprintf(` AND ar.name %s ? || ? || ?`, matchOp)
+		args = append(args, wildcard, filters.Artist, wildcard)
+	}
+	if filters.ArtistPrefix != "" {
+		sqlQuery += fmt.Sprintf(` AND ar.name %s ? || ?`, matchOp)
+		args = append(args, filters.ArtistPrefix, wildcard)
+	}
+	if filters.Album != "" {
+		sqlQuery += fmt.Sprintf(` AND al.name %s ? || ? || ?`, matchOp)
+		args = append(args, wildcard, filters.Album, wildcard)
+	}
+	if filters.Genre != "" {
+		sqlQuery += ` AND g.name = ?`
+		args = append(args, filters.Genre)
+	}
+	if filters.Starred != nil {
+		sqlQuery += ` AND t.starred = ?`
+		args = append(args, *filters.Starred)
+	}
+	if filters.Disliked != nil {
+		sqlQuery += ` AND t.disliked = ?`
+		args = append(args, *filters.Disliked)
+	}
+	if filters.MinRating > 0 {
+		sqlQuery += ` AND t.rating >= ?`
+		args = append(args, filters.MinRating)
+	}
+	if filters.StreamingOnly != nil && *filters.StreamingOnly {
+		sqlQuery += ` AND t.is_streaming = 1`
+	}
+	if filters.LocalOnly != nil && *filters.LocalOnly {
+		sqlQuery += ` AND t.is_streaming = 0`
+	}
+	if filters.DownloadedOnly != nil {
+		sqlQuery += ` AND t.downloaded = ?`
+		args = append(args, *filters.DownloadedOnly)
+	}
+	if filters.Kind != "" {
+		sqlQuery += ` AND t.track_kind = ?`
+		args = append(args, filters.Kind)
+	}
+	return sqlQuery, args
+}
+
+// AlbumResult is one row of SearchAlbums: an album name and its artist,
+// distinct across however many tracks match, with how many of those
+// tracks matched.
+type AlbumResult struct {
+	Album      string `json:"album"`
+	Artist     string `json:"artist"`
+	TrackCount int    `json:"track_count"`
+}
+
+// SearchAlbums returns distinct albums matching query and filters instead
+// of individual tracks, so browsing an artist's discography doesn't mean
+// wading through every track on it. It shares the same predicates as
+// SearchTracks/executeSearchQuery, minus FTS/bm25 ranking, since grouping
+// by album makes a single per-track relevance rank meaningless.
+func (sm *SearchManager) SearchAlbums(query string, filters *SearchFilters) ([]AlbumResult, error) {
+	sqlQuery := `
+		SELECT al.name, COALESCE(ar.name, ''), COUNT(*)
+		FROM tracks t
+		LEFT JOIN artists ar ON ar.id = t.artist_id
+		LEFT JOIN albums al ON al.id = t.album_id
+		LEFT JOIN genres g ON g.id = t.genre_id
+		WHERE al.id IS NOT NULL`
+	var args []interface{}
+
+	matchOp, wildcard := "LIKE", "%"
+	if filters != nil && filters.ExactMatch {
+		matchOp, wildcard = "GLOB", "*"
+	}
+	if query != "" {
+		sqlQuery += fmt.Sprintf(` AND (al.name %s ? || ? || ? OR ar.name %s ? || ? || ?)`, matchOp, matchOp)
+		args = append(args, wildcard, query, wildcard, wildcard, query, wildcard)
+	}
+	sqlQuery, args = appendSearchFilters(sqlQuery, args, filters, matchOp, wildcard)
+	sqlQuery += ` GROUP BY al.id ORDER BY ar.name, al.name LIMIT ?`
+	args = append(args, sm.Limit)
+
+	rows, err := sm.DB.DB.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database: search albums: %w", err)
+	}
+	defer rows.Close()
+
+	var albums []AlbumResult
+	for rows.Next() {
+		var a AlbumResult
+		if err := rows.Scan(&a.Album, &a.Artist, &a.TrackCount); err != nil {
+			return nil, err
+		}
+		albums = append(albums, a)
+	}
+	return albums, rows.Err()
+}
+
+// ArtistResult is one row of SearchArtists: an artist name distinct across
+// however many tracks match, with how many of those tracks matched.
+type ArtistResult struct {
+	Artist     string `json:"artist"`
+	TrackCount int    `json:"track_count"`
+}
+
+// SearchArtists returns distinct artists matching query and filters
+// instead of individual tracks; see SearchAlbums.
+func (sm *SearchManager) SearchArtists(query string, filters *SearchFilters) ([]ArtistResult, error) {
+	sqlQuery := `
+		SELECT ar.name, COUNT(*)
+		FROM tracks t
+		LEFT JOIN artists ar ON ar.id = t.artist_id
+		LEFT JOIN albums al ON al.id = t.album_id
+		LEFT JOIN genres g ON g.id = t.genre_id
+		WHERE ar.id IS NOT NULL`
+	var args []interface{}
+
+	matchOp, wildcard := "LIKE", "%"
+	if filters != nil && filters.ExactMatch {
+		matchOp, wildcard = "GLOB", "*"
+	}
+	if query != "" {
+		sqlQuery += fmt.Sprintf(` AND ar.name %s ? || ? || ?`, matchOp)
+		args = append(args, wildcard, query, wildcard)
+	}
+	sqlQuery, args = appendSearchFilters(sqlQuery, args, filters, matchOp, wildcard)
+	sqlQuery += ` GROUP BY ar.id ORDER BY ar.name LIMIT ?`
+	args = append(args, sm.Limit)
+
+	rows, err := sm.DB.DB.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database: search artists: %w", err)
+	}
+	defer rows.Close()
+
+	var artists []ArtistResult
+	for rows.Next() {
+		var a ArtistResult
+		if err := rows.Scan(&a.Artist, &a.TrackCount); err != nil {
+			return nil, err
+		}
+		artists = append(artists, a)
+	}
+	return artists, rows.Err()
+}
+
+// buildFTSMatchQuery turns a free-text query into an FTS5 MATCH expression:
+// each word becomes a prefix term ("love" -> "love*"), implicitly ANDed
+// together by FTS5's default query syntax, approximating the substring-ish
+// matching callers expect from a plain search box while staying valid FTS5
+// syntax regardless of what punctuation the user typed (anything that
+// isn't a letter or digit is stripped from each word rather than escaped).
+// Returns "" if query has no usable word characters, signaling the caller
+// to fall back to the LIKE-based path.
+func buildFTSMatchQuery(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		var b strings.Builder
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			}
+		}
+		if b.Len() > 0 {
+			terms = append(terms, b.String()+"*")
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// relevanceScore gives a rough 0..1 match-quality score for how query
+// matched t's name/artist/album, independent of the persisted Ranking
+// field. An empty query (no text search, filters only) scores zero.
+func relevanceScore(query string, t Track) float64 {
+	if query == "" {
+		return 0
+	}
+	q := strings.ToLower(query)
+	name := strings.ToLower(t.Name)
+	switch {
+	case name == q:
+		return 1.0
+	case strings.HasPrefix(name, q):
+		return 0.8
+	case strings.Contains(name, q):
+		return 0.6
+	case strings.Contains(strings.ToLower(t.Artist), q), strings.Contains(strings.ToLower(t.Album), q):
+		return 0.4
+	default:
+		return 0.2
+	}
+}
+
+// GetTrackByPersistentID fetches a single track by its Apple Music
+// persistent ID.
+func (m *DatabaseManager) GetTrackByPersistentID(persistentID string) (*Track, error) {
+	var t Track
+	err := m.DB.QueryRow(`
+		SELECT t.id, t.persistent_id, t.name,
+			COALESCE(ar.name, ''), COALESCE(al.name, ''), COALESCE(g.name, ''),
+			t.duration, t.track_kind, t.rating, t.starred, t.disliked, t.play_count,
+			t.last_played, t.date_added, t.ranking, t.is_streaming, t.stream_url, t.downloaded
+		FROM tracks t
+		LEFT JOIN artists ar ON ar.id = t.artist_id
+		LEFT JOIN albums al ON al.id = t.album_id
+		LEFT JOIN genres g ON g.id = t.genre_id
+		WHERE t.persistent_id = ?`, persistentID).
+		Scan(&t.ID, &t.PersistentID, &t.Name, &t.Artist, &t.Album, &t.Genre,
+			&t.Duration, &t.TrackKind, &t.Rating, &t.Starred, &t.Disliked, &t.PlayCount,
+			&t.LastPlayed, &t.DateAdded, &t.Ranking, &t.IsStreaming, &t.StreamURL, &t.Downloaded)
+	if err != nil {
+		return nil, fmt.Errorf("database: get track %q: %w", persistentID, ErrNoTracksFound)
+	}
+	return &t, nil
+}
+
+// SetTrackDisliked updates the cached disliked flag for a track, so
+// subsequent searches with SearchFilters.Disliked reflect it without
+// waiting for the next library refresh.
+func (m *DatabaseManager) SetTrackDisliked(persistentID string, disliked bool) error {
+	return m.enqueueWrite(func() error {
+		res, err := m.DB.Exec(`UPDATE tracks SET disliked = ? WHERE persistent_id = ?`, disliked, persistentID)
+		if err != nil {
+			return fmt.Errorf("database: set disliked %q: %w", persistentID, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("database: set disliked %q: %w", persistentID, ErrNoTracksFound)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,60 @@
+package database
+
+import "testing"
+
+func TestSearchAlbumsAndSearchArtistsReturnDistinctRows(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "md-1", Name: "So What", Artist: "Miles Davis", Album: "Kind of Blue"},
+			{PersistentID: "md-2", Name: "Freddie Freeloader", Artist: "Miles Davis", Album: "Kind of Blue"},
+			{PersistentID: "md-3", Name: "Blue in Green", Artist: "Miles Davis", Album: "Kind of Blue"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	sm := NewSearchManager(db)
+
+	artists, err := sm.SearchArtists("Miles Davis", nil)
+	if err != nil {
+		t.Fatalf("SearchArtists: %v", err)
+	}
+	if len(artists) != 1 {
+		t.Fatalf("got %d artists, want 1", len(artists))
+	}
+	if artists[0].Artist != "Miles Davis" || artists[0].TrackCount != 3 {
+		t.Fatalf("got %+v, want Miles Davis with 3 tracks", artists[0])
+	}
+
+	albums, err := sm.SearchAlbums("Miles Davis", nil)
+	if err != nil {
+		t.Fatalf("SearchAlbums: %v", err)
+	}
+	if len(albums) != 1 {
+		t.Fatalf("got %d albums, want 1", len(albums))
+	}
+	if albums[0].Album != "Kind of Blue" || albums[0].Artist != "Miles Davis" || albums[0].TrackCount != 3 {
+		t.Fatalf("got %+v, want Kind of Blue/Miles Davis with 3 tracks", albums[0])
+	}
+}
+
+func TestSearchAlbumsGroupsByAlbumNotJustArtist(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "md-1", Name: "So What", Artist: "Miles Davis", Album: "Kind of Blue"},
+			{PersistentID: "md-2", Name: "Nardis", Artist: "Miles Davis", Album: "Someday My Prince Will Come"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	sm := NewSearchManager(db)
+
+	albums, err := sm.SearchAlbums("Miles Davis", nil)
+	if err != nil {
+		t.Fatalf("SearchAlbums: %v", err)
+	}
+	if len(albums) != 2 {
+		t.Fatalf("got %d albums, want 2", len(albums))
+	}
+}
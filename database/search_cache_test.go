@@ -0,0 +1,55 @@
+package database
+
+import "testing"
+
+func TestSearchTracksCachesAndClearCacheInvalidates(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{{PersistentID: "p1", Name: "Song", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	sm := NewSearchManager(db)
+
+	first, err := sm.SearchTracks("Song", nil)
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if sm.LastMetrics().CacheHit || len(first) != 1 {
+		t.Fatalf("got hit=%v len=%d, want a miss with 1 result", sm.LastMetrics().CacheHit, len(first))
+	}
+
+	second, err := sm.SearchTracks("Song", nil)
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if !sm.LastMetrics().CacheHit || len(second) != 1 {
+		t.Fatalf("got hit=%v len=%d, want a hit with 1 result", sm.LastMetrics().CacheHit, len(second))
+	}
+
+	// A write that changes the result set (here, deleting the track
+	// directly, standing in for a disliked/rating write elsewhere)
+	// shouldn't be visible until ClearCache runs.
+	if _, err := db.DB.Exec(`DELETE FROM tracks WHERE persistent_id = 'p1'`); err != nil {
+		t.Fatalf("delete track: %v", err)
+	}
+	stale, err := sm.SearchTracks("Song", nil)
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(stale) != 1 {
+		t.Fatalf("got %d results, want the stale cached result (1) before ClearCache", len(stale))
+	}
+
+	sm.ClearCache()
+	fresh, err := sm.SearchTracks("Song", nil)
+	if err != nil {
+		t.Fatalf("SearchTracks after ClearCache: %v", err)
+	}
+	if sm.LastMetrics().CacheHit {
+		t.Fatal("expected a cache miss immediately after ClearCache")
+	}
+	if len(fresh) != 0 {
+		t.Fatalf("got %d results after ClearCache, want 0 now that the track is deleted", len(fresh))
+	}
+}
@@ -0,0 +1,31 @@
+package database
+
+import "testing"
+
+func TestSearchManagerLimitIsPerInstance(t *testing.T) {
+	db := newTestDB(t)
+	for i := 0; i < 5; i++ {
+		track := Track{PersistentID: "p" + string(rune('0'+i)), Name: "Jazz Standard"}
+		if _, err := db.DB.Exec(`INSERT INTO tracks (persistent_id, name) VALUES (?, ?)`, track.PersistentID, track.Name); err != nil {
+			t.Fatalf("insert track %d: %v", i, err)
+		}
+	}
+
+	sm := NewSearchManagerWithLimit(db, 2)
+	results, err := sm.SearchTracks("Jazz", nil)
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2 (limit should apply per-instance)", len(results))
+	}
+
+	unlimited := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	results, err = unlimited.SearchTracks("Jazz", nil)
+	if err != nil {
+		t.Fatalf("SearchTracks: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("got %d results, want 5", len(results))
+	}
+}
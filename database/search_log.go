@@ -0,0 +1,80 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// maxSearchLogRows bounds search_log so opting in doesn't let it grow
+// unbounded: each LogSearch call prunes back down to the most recent rows.
+const maxSearchLogRows = 10000
+
+// searchLogEnvVar opts a process into persisting search queries for
+// analytics. Unset (the default) means LogSearch is a no-op, since query
+// text can be sensitive and shouldn't be recorded without the operator
+// asking for it.
+const searchLogEnvVar = "ITUNES_SEARCH_LOG"
+
+func searchLoggingEnabled() bool {
+	return os.Getenv(searchLogEnvVar) != ""
+}
+
+// PopularSearch is one row of GetPopularSearches' output: a distinct query
+// text and how many times it was logged.
+type PopularSearch struct {
+	Query string `json:"query"`
+	Count int    `json:"count"`
+}
+
+// LogSearch records query (with its filters and how many tracks it
+// matched) to search_log, for later review via GetPopularSearches. It is a
+// no-op unless ITUNES_SEARCH_LOG is set, and prunes search_log back down to
+// maxSearchLogRows after every insert so opting in doesn't grow the
+// database unbounded.
+func (m *DatabaseManager) LogSearch(query string, filters *SearchFilters, resultCount int) error {
+	if !searchLoggingEnabled() {
+		return nil
+	}
+	filterJSON, err := json.Marshal(filters)
+	if err != nil {
+		return fmt.Errorf("database: log search: marshal filters: %w", err)
+	}
+	return m.enqueueWrite(func() error {
+		if _, err := m.DB.Exec(`INSERT INTO search_log (query, filters, result_count) VALUES (?, ?, ?)`,
+			query, string(filterJSON), resultCount); err != nil {
+			return fmt.Errorf("database: log search: %w", err)
+		}
+		if _, err := m.DB.Exec(`DELETE FROM search_log WHERE id NOT IN (SELECT id FROM search_log ORDER BY id DESC LIMIT ?)`,
+			maxSearchLogRows); err != nil {
+			return fmt.Errorf("database: log search: prune: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetPopularSearches returns the limit most frequently logged query texts,
+// most popular first. Returns an empty slice (not an error) when search
+// logging has never been enabled.
+func (m *DatabaseManager) GetPopularSearches(limit int) ([]PopularSearch, error) {
+	rows, err := m.DB.Query(`
+		SELECT query, COUNT(*) AS c
+		FROM search_log
+		GROUP BY query
+		ORDER BY c DESC, query
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("database: get popular searches: %w", err)
+	}
+	defer rows.Close()
+
+	var popular []PopularSearch
+	for rows.Next() {
+		var p PopularSearch
+		if err := rows.Scan(&p.Query, &p.Count); err != nil {
+			return nil, err
+		}
+		popular = append(popular, p)
+	}
+	return popular, rows.Err()
+}
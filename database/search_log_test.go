@@ -0,0 +1,57 @@
+package database
+
+import "testing"
+
+func searchLogRowCount(t *testing.T, db *DatabaseManager) int {
+	t.Helper()
+	var n int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM search_log`).Scan(&n); err != nil {
+		t.Fatalf("count search_log: %v", err)
+	}
+	return n
+}
+
+func TestLogSearchNoOpWhenDisabled(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.LogSearch("windowlicker", nil, 1); err != nil {
+		t.Fatalf("LogSearch: %v", err)
+	}
+	if n := searchLogRowCount(t, db); n != 0 {
+		t.Fatalf("got %d search_log rows, want 0 when ITUNES_SEARCH_LOG is unset", n)
+	}
+}
+
+func TestLogSearchRecordsWhenEnabled(t *testing.T) {
+	t.Setenv("ITUNES_SEARCH_LOG", "1")
+	db := newTestDB(t)
+
+	if err := db.LogSearch("windowlicker", &SearchFilters{Artist: "Aphex Twin"}, 1); err != nil {
+		t.Fatalf("LogSearch: %v", err)
+	}
+	if n := searchLogRowCount(t, db); n != 1 {
+		t.Fatalf("got %d search_log rows, want 1", n)
+	}
+}
+
+func TestGetPopularSearchesOrdersByCount(t *testing.T) {
+	t.Setenv("ITUNES_SEARCH_LOG", "1")
+	db := newTestDB(t)
+
+	for i := 0; i < 3; i++ {
+		if err := db.LogSearch("windowlicker", nil, 1); err != nil {
+			t.Fatalf("LogSearch: %v", err)
+		}
+	}
+	if err := db.LogSearch("other song", nil, 1); err != nil {
+		t.Fatalf("LogSearch: %v", err)
+	}
+
+	popular, err := db.GetPopularSearches(10)
+	if err != nil {
+		t.Fatalf("GetPopularSearches: %v", err)
+	}
+	if len(popular) != 2 || popular[0].Query != "windowlicker" || popular[0].Count != 3 {
+		t.Fatalf("got %+v, want windowlicker first with count 3", popular)
+	}
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestSearchTracksWithTimeoutReturnsPartialResultsOnDeadline seeds a
+// moderately large dataset and searches it with an already-expired
+// deadline, so the query is cancelled deterministically (independent of
+// how fast SQLite actually is against a tiny in-memory test DB) and the
+// timeout path is exercised every run rather than only under load.
+func TestSearchTracksWithTimeoutReturnsPartialResultsOnDeadline(t *testing.T) {
+	db := newTestDB(t)
+	tracks := make([]RefreshTrack, 0, 500)
+	for i := 0; i < 500; i++ {
+		tracks = append(tracks, RefreshTrack{
+			PersistentID: fmt.Sprintf("p%d", i),
+			Name:         fmt.Sprintf("Track %d", i),
+			Artist:       "Timeout Artist",
+		})
+	}
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{Tracks: tracks}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	sm := NewSearchManager(db)
+
+	_, err := sm.SearchTracksWithTimeout("Track", nil, -1*time.Second)
+	if err == nil {
+		t.Fatal("SearchTracksWithTimeout: want an error for an already-expired deadline, got nil")
+	}
+	if !errors.Is(err, ErrSearchTimeout) {
+		t.Fatalf("got error %v, want one wrapping ErrSearchTimeout", err)
+	}
+	var timeoutErr *SearchTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("got error %v, want a *SearchTimeoutError", err)
+	}
+	if !sm.LastMetrics().TimedOut {
+		t.Fatal("LastMetrics().TimedOut = false, want true after a deadline-cancelled search")
+	}
+}
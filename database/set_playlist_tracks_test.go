@@ -0,0 +1,49 @@
+package database
+
+import "testing"
+
+func TestSetPlaylistTracksByPersistentID(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "One"},
+			{PersistentID: "p2", Name: "Two"},
+		},
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "Favorites"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	notFound, err := db.SetPlaylistTracksByPersistentID("pl1", []string{"p2", "p1", "does-not-exist"})
+	if err != nil {
+		t.Fatalf("SetPlaylistTracksByPersistentID: %v", err)
+	}
+	if len(notFound) != 1 || notFound[0] != "does-not-exist" {
+		t.Fatalf("notFound = %v, want [does-not-exist]", notFound)
+	}
+
+	playlist, err := db.GetPlaylistByPersistentID("pl1")
+	if err != nil {
+		t.Fatalf("GetPlaylistByPersistentID: %v", err)
+	}
+	if playlist.TrackCount != 2 {
+		t.Fatalf("TrackCount = %d, want 2", playlist.TrackCount)
+	}
+
+	tracks, err := db.GetPlaylistTracks(playlist.ID)
+	if err != nil {
+		t.Fatalf("GetPlaylistTracks: %v", err)
+	}
+	if len(tracks) != 2 || tracks[0].Name != "Two" || tracks[1].Name != "One" {
+		t.Fatalf("tracks = %+v, want [Two, One] in order", tracks)
+	}
+}
+
+func TestSetPlaylistTracksByPersistentIDUnknownPlaylist(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.SetPlaylistTracksByPersistentID("does-not-exist", nil); err == nil {
+		t.Fatal("expected an error for an unknown playlist")
+	}
+}
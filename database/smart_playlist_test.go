@@ -0,0 +1,64 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestListPlaylistsFiltersBySmart(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{{PersistentID: "t1", Name: "One"}},
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "90s Rock", Smart: true, TrackIDs: []string{"t1"}},
+			{PersistentID: "pl2", Name: "Favorites"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	smart := true
+	smartOnly, err := db.ListPlaylists(&smart, false, 0, 0)
+	if err != nil {
+		t.Fatalf("ListPlaylists(smart=true): %v", err)
+	}
+	if smartOnly.Total != 1 || len(smartOnly.Playlists) != 1 || smartOnly.Playlists[0].PersistentID != "pl1" {
+		t.Fatalf("got %+v, want only pl1", smartOnly)
+	}
+
+	notSmart := false
+	regularOnly, err := db.ListPlaylists(&notSmart, false, 0, 0)
+	if err != nil {
+		t.Fatalf("ListPlaylists(smart=false): %v", err)
+	}
+	if regularOnly.Total != 1 || len(regularOnly.Playlists) != 1 || regularOnly.Playlists[0].PersistentID != "pl2" {
+		t.Fatalf("got %+v, want only pl2", regularOnly)
+	}
+
+	all, err := db.ListPlaylists(nil, false, 0, 0)
+	if err != nil {
+		t.Fatalf("ListPlaylists(nil): %v", err)
+	}
+	if all.Total != 2 || len(all.Playlists) != 2 {
+		t.Fatalf("got %d playlists (total %d), want 2", len(all.Playlists), all.Total)
+	}
+}
+
+func TestSmartPlaylistWritesAreRejected(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{{PersistentID: "t1", Name: "One"}},
+		Playlists: []RefreshPlaylist{
+			{PersistentID: "pl1", Name: "90s Rock", Smart: true, TrackIDs: []string{"t1"}},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	if _, err := db.SetPlaylistTracksByPersistentID("pl1", []string{"t1"}); !errors.Is(err, ErrSmartPlaylist) {
+		t.Fatalf("SetPlaylistTracksByPersistentID: got %v, want ErrSmartPlaylist", err)
+	}
+	if err := db.MovePlaylistTrackByPersistentID("pl1", "t1", 1); !errors.Is(err, ErrSmartPlaylist) {
+		t.Fatalf("MovePlaylistTrackByPersistentID: got %v, want ErrSmartPlaylist", err)
+	}
+}
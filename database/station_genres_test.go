@@ -0,0 +1,33 @@
+package database
+
+import "testing"
+
+func TestListStationGenresOrdersByCountAndExcludesUnknown(t *testing.T) {
+	db := newTestDB(t)
+
+	stations := []RadioStation{
+		{Name: "A", URL: "https://a.example.com", Genre: "Jazz"},
+		{Name: "B", URL: "https://b.example.com", Genre: "Jazz"},
+		{Name: "C", URL: "https://c.example.com", Genre: "Rock"},
+		{Name: "D", URL: "https://d.example.com"}, // no genre -> "Unknown"
+	}
+	for i := range stations {
+		if err := db.AddRadioStation(&stations[i]); err != nil {
+			t.Fatalf("AddRadioStation: %v", err)
+		}
+	}
+
+	genres, err := db.ListStationGenres()
+	if err != nil {
+		t.Fatalf("ListStationGenres: %v", err)
+	}
+	if len(genres) != 2 {
+		t.Fatalf("got %d genres, want 2 (Unknown excluded)", len(genres))
+	}
+	if genres[0].Genre != "Jazz" || genres[0].Count != 2 {
+		t.Errorf("got first=%+v, want Jazz/2 (most popular first)", genres[0])
+	}
+	if genres[1].Genre != "Rock" || genres[1].Count != 1 {
+		t.Errorf("got second=%+v, want Rock/1", genres[1])
+	}
+}
@@ -0,0 +1,24 @@
+package database
+
+import "testing"
+
+func TestGetRadioStationByID(t *testing.T) {
+	db := newTestDB(t)
+
+	station := &RadioStation{Name: "Test FM", URL: "https://example.com/stream", Homepage: "https://example.com"}
+	if err := db.AddRadioStation(station); err != nil {
+		t.Fatalf("AddRadioStation: %v", err)
+	}
+
+	got, err := db.GetRadioStationByID(station.ID)
+	if err != nil {
+		t.Fatalf("GetRadioStationByID: %v", err)
+	}
+	if got.Name != "Test FM" || got.Homepage != "https://example.com" {
+		t.Fatalf("got %+v, want name=Test FM homepage=https://example.com", got)
+	}
+
+	if _, err := db.GetRadioStationByID(station.ID + 1000); err != ErrStationNotFound {
+		t.Fatalf("got err=%v, want ErrStationNotFound", err)
+	}
+}
@@ -0,0 +1,30 @@
+package database
+
+import "testing"
+
+func TestValidateStationExportRejectsMissingName(t *testing.T) {
+	err := ValidateStationExport(RadioStationExport{URL: "https://example.com/stream"}, 0)
+	if err == nil {
+		t.Fatal("expected an error for a missing name")
+	}
+}
+
+func TestValidateStationExportRejectsBadURL(t *testing.T) {
+	cases := []RadioStationExport{
+		{Name: "No URL"},
+		{Name: "Not a URL", URL: "not-a-url"},
+		{Name: "Wrong scheme", URL: "ftp://example.com/stream"},
+	}
+	for _, e := range cases {
+		if err := ValidateStationExport(e, 0); err == nil {
+			t.Errorf("ValidateStationExport(%+v) = nil, want error", e)
+		}
+	}
+}
+
+func TestValidateStationExportAcceptsValidEntry(t *testing.T) {
+	e := RadioStationExport{Name: "Example FM", URL: "https://example.com/stream"}
+	if err := ValidateStationExport(e, 0); err != nil {
+		t.Fatalf("ValidateStationExport: %v", err)
+	}
+}
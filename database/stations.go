@@ -0,0 +1,480 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// normalizeStationURL unifies scheme case and strips the "app=music" query
+// parameter Apple Music share links append, so near-duplicate URLs (http
+// vs https, with/without ?app=music) compare equal for dedup purposes.
+func normalizeStationURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(raw))
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	if parsed.Scheme == "http" {
+		parsed.Scheme = "https"
+	}
+	parsed.Host = strings.ToLower(parsed.Host)
+	q := parsed.Query()
+	q.Del("app")
+	parsed.RawQuery = q.Encode()
+	return strings.TrimSuffix(parsed.String(), "?")
+}
+
+// ValidateStationExport checks that e has the fields required to become a
+// usable RadioStation row: a non-empty name and a URL with an http(s)
+// scheme and host. It returns a descriptive error naming the entry
+// (falling back to its URL or index) when validation fails, so callers
+// like import-stations can report which entries were skipped and why.
+func ValidateStationExport(e RadioStationExport, index int) error {
+	label := e.Name
+	if label == "" {
+		label = e.URL
+	}
+	if label == "" {
+		label = fmt.Sprintf("entry %d", index)
+	}
+	if e.Name == "" {
+		return fmt.Errorf("%s: missing name", label)
+	}
+	if err := validateStationURL(e.URL); err != nil {
+		return fmt.Errorf("%s: %w", label, err)
+	}
+	return nil
+}
+
+// validateStationURL rejects empty, unparsable, or non-http(s) URLs.
+func validateStationURL(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return fmt.Errorf("missing url")
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", raw, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("invalid url %q: scheme must be http or https", raw)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("invalid url %q: missing host", raw)
+	}
+	return nil
+}
+
+// UpsertRadioStation inserts station, or updates an existing row's
+// name/description/genre/homepage if a cached station's (normalized) URL
+// already matches. This is the right primitive for re-importing a
+// refreshed station list, since ImportRadioStations without Upsert just
+// skips anything already present instead of picking up metadata changes.
+func (m *DatabaseManager) UpsertRadioStation(station *RadioStation) error {
+	return m.enqueueWrite(func() error {
+		genreID, err := m.getOrCreateGenre(station.Genre)
+		if err != nil {
+			return err
+		}
+
+		existing, err := m.FindStationByURL(station.URL)
+		if err != nil && err != ErrStationNotFound {
+			return err
+		}
+		if err == ErrStationNotFound {
+			res, err := m.DB.Exec(`
+				INSERT INTO radio_stations (name, url, description, genre_id, homepage, is_active)
+				VALUES (?, ?, ?, ?, ?, 1)`,
+				station.Name, station.URL, station.Description, genreID, station.Homepage)
+			if err != nil {
+				return fmt.Errorf("database: upsert radio station: %w", err)
+			}
+			station.ID, _ = res.LastInsertId()
+			return nil
+		}
+
+		if _, err := m.DB.Exec(`
+			UPDATE radio_stations SET name = ?, description = ?, genre_id = ?, homepage = ?
+			WHERE id = ?`,
+			station.Name, station.Description, genreID, station.Homepage, existing.ID); err != nil {
+			return fmt.Errorf("database: upsert radio station: %w", err)
+		}
+		station.ID = existing.ID
+		return nil
+	})
+}
+
+// AddRadioStation inserts a new station, erroring if the URL already
+// exists.
+func (m *DatabaseManager) AddRadioStation(station *RadioStation) error {
+	return m.enqueueWrite(func() error {
+		genreID, err := m.getOrCreateGenre(station.Genre)
+		if err != nil {
+			return err
+		}
+		res, err := m.DB.Exec(`
+			INSERT INTO radio_stations (name, url, description, genre_id, homepage, is_active)
+			VALUES (?, ?, ?, ?, ?, 1)`,
+			station.Name, station.URL, station.Description, genreID, station.Homepage)
+		if err != nil {
+			return fmt.Errorf("database: add radio station: %w", err)
+		}
+		station.ID, _ = res.LastInsertId()
+		return nil
+	})
+}
+
+func (m *DatabaseManager) getOrCreateGenre(name string) (int64, error) {
+	if name == "" {
+		name = "Unknown"
+	}
+	var id int64
+	err := m.DB.QueryRow(`SELECT id FROM genres WHERE name = ?`, name).Scan(&id)
+	if err == sql.ErrNoRows {
+		res, err := m.DB.Exec(`INSERT INTO genres (name) VALUES (?)`, name)
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	}
+	return id, err
+}
+
+// SearchRadioStations performs a simple LIKE search over name/description,
+// optionally filtered, capped at limit results.
+func (m *DatabaseManager) SearchRadioStations(query string, filters *RadioStationFilters, limit int) ([]RadioStation, error) {
+	sqlQuery := `
+		SELECT s.id, s.name, s.url, s.description, COALESCE(g.name, ''), COALESCE(s.genre_id, 0),
+			s.homepage, s.is_active, s.created_at
+		FROM radio_stations s
+		LEFT JOIN genres g ON g.id = s.genre_id
+		WHERE (? = '' OR s.name LIKE '%' || ? || '%' OR s.description LIKE '%' || ? || '%')`
+	args := []interface{}{query, query, query}
+	if filters != nil {
+		if filters.Genre != "" {
+			sqlQuery += ` AND g.name = ?`
+			args = append(args, filters.Genre)
+		}
+		if filters.ActiveOnly {
+			sqlQuery += ` AND s.is_active = 1`
+		}
+	}
+	sqlQuery += ` ORDER BY s.name LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := m.DB.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database: search radio stations: %w", err)
+	}
+	defer rows.Close()
+	return scanStations(rows)
+}
+
+func scanStations(rows *sql.Rows) ([]RadioStation, error) {
+	var stations []RadioStation
+	for rows.Next() {
+		var s RadioStation
+		if err := rows.Scan(&s.ID, &s.Name, &s.URL, &s.Description, &s.Genre, &s.GenreID,
+			&s.Homepage, &s.IsActive, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		stations = append(stations, s)
+	}
+	return stations, rows.Err()
+}
+
+// FindStationByURL looks up a cached station by stream URL, normalizing
+// both sides the same way ImportRadioStations' dedup does (scheme case,
+// stripping "?app=music"), so a station saved as "http://...?app=music"
+// still matches the https URL PlayStreamURL was actually given. Returns
+// ErrStationNotFound if no cached station's URL matches.
+func (m *DatabaseManager) FindStationByURL(rawURL string) (*RadioStation, error) {
+	target := normalizeStationURL(rawURL)
+
+	rows, err := m.DB.Query(`
+		SELECT s.id, s.name, s.url, s.description, COALESCE(g.name, ''), COALESCE(s.genre_id, 0),
+			s.homepage, s.is_active, s.created_at
+		FROM radio_stations s
+		LEFT JOIN genres g ON g.id = s.genre_id`)
+	if err != nil {
+		return nil, fmt.Errorf("database: find station by url: %w", err)
+	}
+	defer rows.Close()
+
+	stations, err := scanStations(rows)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stations {
+		if normalizeStationURL(stations[i].URL) == target {
+			return &stations[i], nil
+		}
+	}
+	return nil, ErrStationNotFound
+}
+
+// GetRadioStationByID returns the station with the given ID, or
+// ErrStationNotFound if no such station exists.
+func (m *DatabaseManager) GetRadioStationByID(id int64) (*RadioStation, error) {
+	var s RadioStation
+	err := m.DB.QueryRow(`
+		SELECT s.id, s.name, s.url, s.description, COALESCE(g.name, ''), COALESCE(s.genre_id, 0),
+			s.homepage, s.is_active, s.created_at
+		FROM radio_stations s
+		LEFT JOIN genres g ON g.id = s.genre_id
+		WHERE s.id = ?`, id).Scan(&s.ID, &s.Name, &s.URL, &s.Description, &s.Genre, &s.GenreID,
+		&s.Homepage, &s.IsActive, &s.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrStationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database: get radio station: %w", err)
+	}
+	return &s, nil
+}
+
+// ListRadioStations returns every station matching filters, ordered by
+// name, with no FTS involvement and no implicit cap — unlike
+// SearchRadioStations("", filters, limit), it never silently truncates
+// unless filters.Limit is set. Pass nil for no filtering.
+func (m *DatabaseManager) ListRadioStations(filters *RadioStationFilters) ([]RadioStation, error) {
+	sqlQuery := `
+		SELECT s.id, s.name, s.url, s.description, COALESCE(g.name, ''), COALESCE(s.genre_id, 0),
+			s.homepage, s.is_active, s.created_at
+		FROM radio_stations s
+		LEFT JOIN genres g ON g.id = s.genre_id
+		WHERE 1 = 1`
+	var args []interface{}
+
+	if filters != nil {
+		if filters.Genre != "" {
+			sqlQuery += ` AND g.name = ?`
+			args = append(args, filters.Genre)
+		}
+		if filters.ActiveOnly {
+			sqlQuery += ` AND s.is_active = 1`
+		}
+	}
+	sqlQuery += ` ORDER BY s.name`
+	if filters != nil && filters.Limit > 0 {
+		sqlQuery += ` LIMIT ? OFFSET ?`
+		args = append(args, filters.Limit, filters.Offset)
+	}
+
+	rows, err := m.DB.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database: list radio stations: %w", err)
+	}
+	defer rows.Close()
+	return scanStations(rows)
+}
+
+// DeleteRadioStationResult is DeleteRadioStation's change set.
+type DeleteRadioStationResult struct {
+	Deleted bool `json:"deleted"`
+}
+
+// DeleteRadioStation removes a station by ID. When dryRun is true, the
+// delete is rolled back instead of committed, so callers can preview it.
+func (m *DatabaseManager) DeleteRadioStation(id int64, dryRun bool) (*DeleteRadioStationResult, error) {
+	var result *DeleteRadioStationResult
+	err := m.enqueueWrite(func() error {
+		tx, err := m.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		res, err := tx.Exec(`DELETE FROM radio_stations WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		result = &DeleteRadioStationResult{Deleted: n > 0}
+
+		if dryRun {
+			return nil
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteInactiveStations removes every station with is_active = 0, e.g.
+// ones a URL health check has flagged as dead, returning the count
+// removed. When dryRun is true, the delete is rolled back instead of
+// committed, so callers can preview it before pruning for real.
+func (m *DatabaseManager) DeleteInactiveStations(dryRun bool) (int, error) {
+	var n int64
+	err := m.enqueueWrite(func() error {
+		tx, err := m.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		res, err := tx.Exec(`DELETE FROM radio_stations WHERE is_active = 0`)
+		if err != nil {
+			return fmt.Errorf("database: delete inactive stations: %w", err)
+		}
+		n, err = res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return nil
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// ImportResult reports how an ImportRadioStations call went.
+type ImportResult struct {
+	Imported   int
+	Updated    int
+	Skipped    int
+	Duplicates int
+	Errors     []string
+}
+
+// ImportOptions controls ImportRadioStations behavior.
+type ImportOptions struct {
+	// Dedupe normalizes URLs (scheme case, stripping "?app=music") before
+	// the uniqueness check, so near-duplicates don't slip in as separate
+	// rows. Duplicates found this way are counted in Duplicates rather
+	// than Imported. Ignored when Upsert is set, since upserting the same
+	// URL twice is already harmless.
+	Dedupe bool
+	// Upsert updates an existing station's metadata instead of skipping it
+	// when its (normalized) URL is already cached, via UpsertRadioStation.
+	// Use this to re-import a refreshed station list and pick up name/
+	// description/genre/homepage changes.
+	Upsert bool
+}
+
+// ImportRadioStations inserts stations, skipping ones whose URL already
+// exists unless opts.Upsert is set, in which case existing stations are
+// updated instead.
+func (m *DatabaseManager) ImportRadioStations(stations []RadioStation, opts ImportOptions) (*ImportResult, error) {
+	result := &ImportResult{}
+
+	if opts.Upsert {
+		for i := range stations {
+			s := stations[i]
+			_, err := m.FindStationByURL(s.URL)
+			existed := err == nil
+			if err != nil && err != ErrStationNotFound {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", s.URL, err))
+				continue
+			}
+			if err := m.UpsertRadioStation(&s); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", s.URL, err))
+				continue
+			}
+			if existed {
+				result.Updated++
+			} else {
+				result.Imported++
+			}
+		}
+		if result.Imported == 0 && result.Updated == 0 && len(stations) > 0 {
+			return result, fmt.Errorf("database: import stations: 0 of %d stations imported or updated (%d errors)",
+				len(stations), len(result.Errors))
+		}
+		return result, nil
+	}
+
+	var seen map[string]bool
+	if opts.Dedupe {
+		seen = make(map[string]bool, len(stations))
+		existing, err := m.ListRadioStations(nil)
+		if err != nil {
+			return nil, fmt.Errorf("database: import stations: %w", err)
+		}
+		for _, e := range existing {
+			seen[normalizeStationURL(e.URL)] = true
+		}
+	}
+
+	for i := range stations {
+		s := stations[i]
+
+		if opts.Dedupe {
+			key := normalizeStationURL(s.URL)
+			if seen[key] {
+				result.Duplicates++
+				continue
+			}
+			seen[key] = true
+		}
+
+		genreID, err := m.getOrCreateGenre(s.Genre)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", s.URL, err))
+			continue
+		}
+		res, err := m.DB.Exec(`
+			INSERT OR IGNORE INTO radio_stations (name, url, description, genre_id, homepage, is_active)
+			VALUES (?, ?, ?, ?, ?, 1)`,
+			s.Name, s.URL, s.Description, genreID, s.Homepage)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", s.URL, err))
+			continue
+		}
+		affected, _ := res.RowsAffected()
+		if affected == 0 {
+			result.Skipped++
+			continue
+		}
+		result.Imported++
+	}
+	if result.Imported == 0 && len(stations) > 0 {
+		return result, fmt.Errorf("database: import stations: 0 of %d stations imported (%d skipped, %d duplicates, %d errors)",
+			len(stations), result.Skipped, result.Duplicates, len(result.Errors))
+	}
+	return result, nil
+}
+
+// ListStationGenres returns genres that have at least one radio station,
+// with counts, ordered by count descending, excluding the catch-all
+// "Unknown" genre assigned when a station has no genre.
+func (m *DatabaseManager) ListStationGenres() ([]GenreCount, error) {
+	rows, err := m.DB.Query(`
+		SELECT g.name, COUNT(*)
+		FROM radio_stations s
+		JOIN genres g ON g.id = s.genre_id
+		WHERE g.name != 'Unknown'
+		GROUP BY g.name
+		ORDER BY COUNT(*) DESC, g.name`)
+	if err != nil {
+		return nil, fmt.Errorf("database: list station genres: %w", err)
+	}
+	defer rows.Close()
+
+	var genres []GenreCount
+	for rows.Next() {
+		var gc GenreCount
+		if err := rows.Scan(&gc.Genre, &gc.Count); err != nil {
+			return nil, err
+		}
+		genres = append(genres, gc)
+	}
+	return genres, rows.Err()
+}
+
+// ExportStations returns every station for the export CLI command.
+func (m *DatabaseManager) ExportStations() ([]RadioStation, error) {
+	return m.ListRadioStations(nil)
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newTestDB(t *testing.T) *DatabaseManager {
+	t.Helper()
+	db, err := NewDatabaseManager(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestListRadioStationsDoesNotTruncate(t *testing.T) {
+	db := newTestDB(t)
+
+	const total = DefaultSearchLimit + 25
+	for i := 0; i < total; i++ {
+		station := RadioStation{
+			Name: fmt.Sprintf("Station %03d", i),
+			URL:  fmt.Sprintf("https://example.com/stream/%d", i),
+		}
+		if err := db.AddRadioStation(&station); err != nil {
+			t.Fatalf("AddRadioStation(%d): %v", i, err)
+		}
+	}
+
+	stations, err := db.ListRadioStations(nil)
+	if err != nil {
+		t.Fatalf("ListRadioStations: %v", err)
+	}
+	if len(stations) != total {
+		t.Fatalf("got %d stations, want %d (SearchRadioStations would have capped at the default limit)", len(stations), total)
+	}
+}
@@ -0,0 +1,41 @@
+package database
+
+import "testing"
+
+func TestGetRatingDistributionAndStarredBreakdown(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "r1", Name: "Song One", Artist: "Artist", Rating: 100, Starred: true},
+			{PersistentID: "r2", Name: "Song Two", Artist: "Artist", Rating: 100, Starred: true},
+			{PersistentID: "r3", Name: "Song Three", Artist: "Artist", Rating: 60},
+			{PersistentID: "r4", Name: "Song Four", Artist: "Artist", Rating: 0},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	dist, err := db.GetRatingDistribution()
+	if err != nil {
+		t.Fatalf("GetRatingDistribution: %v", err)
+	}
+	want := map[int]int{0: 1, 20: 0, 40: 0, 60: 1, 80: 0, 100: 2}
+	for bucket, count := range want {
+		if dist[bucket] != count {
+			t.Errorf("dist[%d] = %d, want %d", bucket, dist[bucket], count)
+		}
+	}
+
+	starred, unstarred, err := db.GetStarredBreakdown()
+	if err != nil {
+		t.Fatalf("GetStarredBreakdown: %v", err)
+	}
+	if starred != 2 {
+		t.Errorf("starred = %d, want 2", starred)
+	}
+	if unstarred != 2 {
+		t.Errorf("unstarred = %d, want 2", unstarred)
+	}
+}
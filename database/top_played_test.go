@@ -0,0 +1,35 @@
+package database
+
+import "testing"
+
+func TestGetTopTracksAndArtistsOrdering(t *testing.T) {
+	db := newTestDB(t)
+
+	tracks := []RefreshTrack{
+		{PersistentID: "p1", Name: "Quiet", Artist: "Artist A", PlayCount: 2},
+		{PersistentID: "p2", Name: "Loud", Artist: "Artist A", PlayCount: 10},
+		{PersistentID: "p3", Name: "Medium", Artist: "Artist B", PlayCount: 5},
+	}
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{Tracks: tracks}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	top, err := db.GetTopTracks(2)
+	if err != nil {
+		t.Fatalf("GetTopTracks: %v", err)
+	}
+	if len(top) != 2 || top[0].Name != "Loud" || top[1].Name != "Medium" {
+		t.Fatalf("got %+v, want [Loud, Medium]", top)
+	}
+
+	artists, err := db.GetTopArtists(10)
+	if err != nil {
+		t.Fatalf("GetTopArtists: %v", err)
+	}
+	if len(artists) != 2 || artists[0].Artist != "Artist A" || artists[0].PlayCount != 12 {
+		t.Fatalf("got %+v, want Artist A first with play_count=12", artists)
+	}
+	if artists[1].Artist != "Artist B" || artists[1].PlayCount != 5 {
+		t.Fatalf("got %+v, want Artist B second with play_count=5", artists)
+	}
+}
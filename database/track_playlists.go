@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetPlaylistNamesForTrackIDs returns, for each of trackIDs, the names of
+// the playlists it belongs to, ordered by each playlist's position within
+// that track's membership rows and then by playlist name for a stable
+// order. Tracks with no matches are omitted from the result map. This
+// fetches every track's playlist names in a single WHERE track_id IN (...)
+// query, so a caller enriching a whole page of search results with
+// playlist membership doesn't need to query once per track.
+func (m *DatabaseManager) GetPlaylistNamesForTrackIDs(trackIDs []int64) (map[int64][]string, error) {
+	result := make(map[int64][]string, len(trackIDs))
+	if len(trackIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.Repeat("?,", len(trackIDs))
+	placeholders = placeholders[:len(placeholders)-1]
+	args := make([]interface{}, len(trackIDs))
+	for i, id := range trackIDs {
+		args[i] = id
+	}
+
+	rows, err := m.DB.Query(`
+		SELECT pt.track_id, p.name
+		FROM playlist_tracks pt
+		JOIN playlists p ON p.id = pt.playlist_id
+		WHERE pt.track_id IN (`+placeholders+`)
+		ORDER BY pt.track_id, pt.position, p.name`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database: get playlist names for track ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var trackID int64
+		var name string
+		if err := rows.Scan(&trackID, &name); err != nil {
+			return nil, err
+		}
+		result[trackID] = append(result[trackID], name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// playlistNamesForTrackNaive looks up a single track's playlist names with
+// one query, the way a per-track loop would. It exists only so the tests
+// and benchmark below have a one-query-per-track baseline to compare
+// GetPlaylistNamesForTrackIDs's single batched query against.
+func playlistNamesForTrackNaive(m *DatabaseManager, trackID int64) ([]string, error) {
+	rows, err := m.DB.Query(`
+		SELECT p.name
+		FROM playlist_tracks pt
+		JOIN playlists p ON p.id = pt.playlist_id
+		WHERE pt.track_id = ?
+		ORDER BY pt.position, p.name`, trackID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func seedTracksAcrossPlaylists(t *testing.T, db *DatabaseManager, numTracks, numPlaylists int) []int64 {
+	t.Helper()
+
+	tracks := make([]RefreshTrack, numTracks)
+	trackIDs := make([]string, numTracks)
+	for i := 0; i < numTracks; i++ {
+		id := fmt.Sprintf("t%03d", i)
+		tracks[i] = RefreshTrack{PersistentID: id, Name: fmt.Sprintf("Track %03d", i)}
+		trackIDs[i] = id
+	}
+
+	playlists := make([]RefreshPlaylist, numPlaylists)
+	for i := 0; i < numPlaylists; i++ {
+		// Every playlist gets a different, overlapping slice of tracks so a
+		// track can belong to more than one playlist.
+		playlists[i] = RefreshPlaylist{
+			PersistentID: fmt.Sprintf("pl%03d", i),
+			Name:         fmt.Sprintf("Playlist %03d", i),
+			TrackIDs:     trackIDs[i*numTracks/numPlaylists:],
+		}
+	}
+
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{Tracks: tracks, Playlists: playlists}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	rows, err := db.DB.Query(`SELECT id FROM tracks ORDER BY id`)
+	if err != nil {
+		t.Fatalf("query track ids: %v", err)
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("scan track id: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestGetPlaylistNamesForTrackIDsMatchesPerTrackLookups(t *testing.T) {
+	db := newTestDB(t)
+	trackIDs := seedTracksAcrossPlaylists(t, db, 20, 4)
+
+	batched, err := db.GetPlaylistNamesForTrackIDs(trackIDs)
+	if err != nil {
+		t.Fatalf("GetPlaylistNamesForTrackIDs: %v", err)
+	}
+
+	for _, id := range trackIDs {
+		want, err := playlistNamesForTrackNaive(db, id)
+		if err != nil {
+			t.Fatalf("playlistNamesForTrackNaive(%d): %v", id, err)
+		}
+		got := batched[id]
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("track %d: GetPlaylistNamesForTrackIDs = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestGetPlaylistNamesForTrackIDsEmptyInput(t *testing.T) {
+	db := newTestDB(t)
+	result, err := db.GetPlaylistNamesForTrackIDs(nil)
+	if err != nil {
+		t.Fatalf("GetPlaylistNamesForTrackIDs(nil): %v", err)
+	}
+	if len(result) != 0 {
+		t.Fatalf("got %v, want empty map", result)
+	}
+}
+
+func BenchmarkPlaylistNamesPerTrackLoop(b *testing.B) {
+	db, err := NewDatabaseManager(fmt.Sprintf("file:%s?mode=memory&cache=shared", b.Name()))
+	if err != nil {
+		b.Fatalf("NewDatabaseManager: %v", err)
+	}
+	defer db.Close()
+	trackIDs := seedTracksAcrossPlaylistsForBench(b, db, 1000, 20)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, id := range trackIDs {
+			if _, err := playlistNamesForTrackNaive(db, id); err != nil {
+				b.Fatalf("playlistNamesForTrackNaive: %v", err)
+			}
+		}
+	}
+}
+
+func BenchmarkPlaylistNamesBatched(b *testing.B) {
+	db, err := NewDatabaseManager(fmt.Sprintf("file:%s?mode=memory&cache=shared", b.Name()))
+	if err != nil {
+		b.Fatalf("NewDatabaseManager: %v", err)
+	}
+	defer db.Close()
+	trackIDs := seedTracksAcrossPlaylistsForBench(b, db, 1000, 20)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.GetPlaylistNamesForTrackIDs(trackIDs); err != nil {
+			b.Fatalf("GetPlaylistNamesForTrackIDs: %v", err)
+		}
+	}
+}
+
+// seedTracksAcrossPlaylistsForBench mirrors seedTracksAcrossPlaylists but
+// takes a *testing.B, since the two benchmarks above can't share a
+// *testing.T-based helper.
+func seedTracksAcrossPlaylistsForBench(b *testing.B, db *DatabaseManager, numTracks, numPlaylists int) []int64 {
+	b.Helper()
+
+	tracks := make([]RefreshTrack, numTracks)
+	trackIDs := make([]string, numTracks)
+	for i := 0; i < numTracks; i++ {
+		id := fmt.Sprintf("t%03d", i)
+		tracks[i] = RefreshTrack{PersistentID: id, Name: fmt.Sprintf("Track %03d", i)}
+		trackIDs[i] = id
+	}
+
+	playlists := make([]RefreshPlaylist, numPlaylists)
+	for i := 0; i < numPlaylists; i++ {
+		playlists[i] = RefreshPlaylist{
+			PersistentID: fmt.Sprintf("pl%03d", i),
+			Name:         fmt.Sprintf("Playlist %03d", i),
+			TrackIDs:     trackIDs[i*numTracks/numPlaylists:],
+		}
+	}
+
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{Tracks: tracks, Playlists: playlists}); err != nil {
+		b.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	rows, err := db.DB.Query(`SELECT id FROM tracks ORDER BY id`)
+	if err != nil {
+		b.Fatalf("query track ids: %v", err)
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			b.Fatalf("scan track id: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
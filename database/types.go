@@ -0,0 +1,162 @@
+package database
+
+import "time"
+
+// Track is the row shape stored in the tracks table, joined with its
+// denormalized artist/album/genre names for convenience.
+type Track struct {
+	ID           int64      `json:"id" db:"id"`
+	PersistentID string     `json:"persistent_id" db:"persistent_id"`
+	Name         string     `json:"name" db:"name"`
+	Artist       string     `json:"artist" db:"artist"`
+	Album        string     `json:"album" db:"album"`
+	Genre        string     `json:"genre" db:"genre"`
+	Duration     float64    `json:"duration" db:"duration"`
+	TrackKind    string     `json:"track_kind" db:"track_kind"`
+	Rating       int        `json:"rating" db:"rating"`
+	Starred      bool       `json:"starred" db:"starred"`
+	Disliked     bool       `json:"disliked" db:"disliked"`
+	PlayCount    int        `json:"play_count" db:"play_count"`
+	LastPlayed   *time.Time `json:"last_played,omitempty" db:"last_played"`
+	DateAdded    time.Time  `json:"date_added" db:"date_added"`
+	Ranking      float64    `json:"ranking" db:"ranking"`
+	IsStreaming  bool       `json:"is_streaming" db:"is_streaming"`
+	StreamURL    string     `json:"stream_url,omitempty" db:"stream_url"`
+	Downloaded   bool       `json:"downloaded" db:"downloaded"`
+
+	// Relevance is a per-query match score computed by
+	// SearchManager.executeSearchQuery (not stored in the tracks table), so
+	// callers can show match confidence without it being clobbered by, or
+	// clobbering, the persisted Ranking field.
+	Relevance float64 `json:"relevance,omitempty" db:"-"`
+}
+
+// Playlist is the row shape stored in the playlists table.
+type Playlist struct {
+	ID           int64  `json:"id" db:"id"`
+	PersistentID string `json:"persistent_id" db:"persistent_id"`
+	Name         string `json:"name" db:"name"`
+	SpecialKind  string `json:"special_kind" db:"special_kind"`
+	TrackCount   int    `json:"track_count" db:"track_count"`
+	ParentID     *int64 `json:"parent_id,omitempty" db:"parent_id"`
+	Smart        bool   `json:"smart" db:"smart"`
+}
+
+// RadioStation is the row shape stored in the radio_stations table.
+type RadioStation struct {
+	ID          int64     `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	URL         string    `json:"url" db:"url"`
+	Description string    `json:"description" db:"description"`
+	Genre       string    `json:"genre" db:"genre"`
+	GenreID     int64     `json:"genre_id" db:"genre_id"`
+	Homepage    string    `json:"homepage" db:"homepage"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// RadioStationExport is the portable shape used by both export-stations and
+// import-stations, deliberately omitting internal fields (ID, GenreID,
+// CreatedAt) so a file exported from one database re-imports cleanly into
+// another rather than carrying over stale IDs.
+type RadioStationExport struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	Genre       string `json:"genre,omitempty"`
+	Homepage    string `json:"homepage,omitempty"`
+}
+
+// ToExport converts a RadioStation row into its portable export shape.
+func (s RadioStation) ToExport() RadioStationExport {
+	return RadioStationExport{
+		Name:        s.Name,
+		URL:         s.URL,
+		Description: s.Description,
+		Genre:       s.Genre,
+		Homepage:    s.Homepage,
+	}
+}
+
+// FromExport converts a portable RadioStationExport into a RadioStation row
+// ready for AddRadioStation/ImportRadioStations.
+func (e RadioStationExport) FromExport() RadioStation {
+	return RadioStation{
+		Name:        e.Name,
+		URL:         e.URL,
+		Description: e.Description,
+		Genre:       e.Genre,
+		Homepage:    e.Homepage,
+	}
+}
+
+// RadioStationFilters narrows ListRadioStations/SearchRadioStations. Limit
+// and Offset are optional pagination for ListRadioStations; zero means
+// "no limit"/"start from the beginning".
+type RadioStationFilters struct {
+	Genre      string
+	ActiveOnly bool
+	Limit      int
+	Offset     int
+}
+
+// GenreCount pairs a genre name with how many radio stations are tagged
+// with it.
+type GenreCount struct {
+	Genre string `json:"genre"`
+	Count int    `json:"count"`
+}
+
+// KindCount pairs a track_kind value (e.g. "Internet audio stream",
+// "MPEG audio file") with how many tracks have it.
+type KindCount struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// SearchFilters narrows a track search in addition to (or instead of) a
+// free-text query.
+type SearchFilters struct {
+	Artist        string
+	Album         string
+	Genre         string
+	Starred       *bool
+	Disliked      *bool
+	MinRating     int
+	StreamingOnly *bool
+	LocalOnly     *bool
+	// DownloadedOnly, when non-nil, restricts results to tracks available
+	// offline (true) or cloud-only/not-downloaded (false).
+	DownloadedOnly *bool
+	// ExactMatch makes the query/artist/album text match case- and
+	// accent-sensitive. By default a SearchTracks text query goes through
+	// tracks_fts, whose tokenizer folds accents, and other text matches use
+	// LIKE (case-insensitive for ASCII); ExactMatch bypasses tracks_fts and
+	// matches with GLOB instead. Use it to distinguish e.g. "Bjork" from
+	// "Björk" or "ACDC" from "acdc" when the default match would be too loose.
+	ExactMatch bool
+	// UseBM25 controls whether a free-text query is matched through
+	// tracks_fts and ordered by FTS5's bm25() relevance rank instead of the
+	// plain LIKE-based match. Nil (the default) enables it for any
+	// non-empty query that isn't ExactMatch; set explicitly to force it on
+	// or off.
+	UseBM25 *bool
+	// Kind restricts results to tracks whose track_kind matches exactly
+	// (e.g. "Internet audio stream" to find only streams, "MPEG audio
+	// file" for downloaded music), unlike StreamingOnly/LocalOnly which
+	// only distinguish streaming from local.
+	Kind string
+	// ArtistPrefix restricts results to tracks whose artist name starts
+	// with this value, anchored at the start unlike Artist's
+	// anywhere-in-the-name substring match. Useful for disambiguating
+	// artists sharing a common substring (e.g. prefix "The" should not
+	// also pull in artists that merely contain "the" mid-name).
+	ArtistPrefix string
+	// ExactName restricts the free-text query to an exact (case-insensitive)
+	// match against t.name, bypassing FTS/LIKE substring matching entirely.
+	// Unlike FindTrack, which returns a single best guess, this still
+	// honors every other filter and returns all tracks whose title matches
+	// exactly, for scripted playback that needs precise title lookups
+	// (e.g. "So What" without also matching "What").
+	ExactName bool
+}
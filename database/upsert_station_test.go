@@ -0,0 +1,58 @@
+package database
+
+import "testing"
+
+func TestUpsertRadioStationUpdatesExistingRow(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.UpsertRadioStation(&RadioStation{
+		Name: "Jazz24", URL: "https://jazz24.example.com/stream", Description: "smooth jazz",
+	}); err != nil {
+		t.Fatalf("UpsertRadioStation (insert): %v", err)
+	}
+
+	if err := db.UpsertRadioStation(&RadioStation{
+		Name: "Jazz24 HD", URL: "https://jazz24.example.com/stream", Description: "smooth jazz, now in HD",
+	}); err != nil {
+		t.Fatalf("UpsertRadioStation (update): %v", err)
+	}
+
+	stations, err := db.ListRadioStations(nil)
+	if err != nil {
+		t.Fatalf("ListRadioStations: %v", err)
+	}
+	if len(stations) != 1 {
+		t.Fatalf("got %d stations, want 1 (upsert should update, not duplicate)", len(stations))
+	}
+	if stations[0].Name != "Jazz24 HD" || stations[0].Description != "smooth jazz, now in HD" {
+		t.Fatalf("got %+v, want updated name/description", stations[0])
+	}
+}
+
+func TestImportRadioStationsUpsertReImportsChangedMetadata(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.ImportRadioStations([]RadioStation{
+		{Name: "Jazz24", URL: "https://jazz24.example.com/stream", Description: "v1"},
+	}, ImportOptions{}); err != nil {
+		t.Fatalf("ImportRadioStations (seed): %v", err)
+	}
+
+	result, err := db.ImportRadioStations([]RadioStation{
+		{Name: "Jazz24", URL: "https://jazz24.example.com/stream", Description: "v2"},
+	}, ImportOptions{Upsert: true})
+	if err != nil {
+		t.Fatalf("ImportRadioStations (upsert): %v", err)
+	}
+	if result.Imported != 0 || result.Updated != 1 {
+		t.Fatalf("got imported=%d updated=%d, want imported=0 updated=1", result.Imported, result.Updated)
+	}
+
+	stations, err := db.ListRadioStations(nil)
+	if err != nil {
+		t.Fatalf("ListRadioStations: %v", err)
+	}
+	if len(stations) != 1 || stations[0].Description != "v2" {
+		t.Fatalf("got %+v, want a single station updated to description v2", stations)
+	}
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"fmt"
+	"os"
+)
+
+// Vacuum reclaims space freed by deletes (rating/library edits, pruned
+// unused artists/albums/genres, a migration that dropped rows) by
+// rewriting the database file, then runs ANALYZE so the query planner's
+// table statistics reflect the post-VACUUM shape. VACUUM takes an
+// exclusive lock on the whole database for its duration and bypasses the
+// write queue entirely (SQLite doesn't allow it inside a transaction, and
+// the queue's withRetry/busy-timeout handling only matters for the kind of
+// brief contention an ordinary write hits, not a bulk rewrite callers
+// should only run when they know writes can pause, e.g. not mid-refresh).
+//
+// Vacuum refuses to run against a database file it can't write to, since
+// otherwise VACUUM would fail partway through with a less useful SQLite
+// error.
+func (m *DatabaseManager) Vacuum() error {
+	if info, err := os.Stat(m.Path); err == nil && info.Mode().Perm()&0o200 == 0 {
+		return fmt.Errorf("database: vacuum %q: file is read-only", m.Path)
+	}
+	if _, err := m.DB.Exec(`VACUUM`); err != nil {
+		return fmt.Errorf("database: vacuum: %w", err)
+	}
+	if _, err := m.DB.Exec(`ANALYZE`); err != nil {
+		return fmt.Errorf("database: analyze: %w", err)
+	}
+	return nil
+}
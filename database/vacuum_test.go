@@ -0,0 +1,89 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestVacuumShrinksFileAndKeepsDatabaseValid inserts enough tracks to
+// occupy multiple database pages, deletes most of them, then vacuums and
+// confirms the file actually shrank and PRAGMA integrity_check still
+// reports the database as valid.
+func TestVacuumShrinksFileAndKeepsDatabaseValid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vacuum.db")
+	db, err := NewDatabaseManager(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	defer db.Close()
+
+	tracks := make([]RefreshTrack, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		tracks = append(tracks, RefreshTrack{
+			PersistentID: fmt.Sprintf("p%d", i),
+			Name:         fmt.Sprintf("Track %d with some extra padding text to occupy space", i),
+			Artist:       fmt.Sprintf("Artist %d", i),
+			Album:        fmt.Sprintf("Album %d", i),
+		})
+	}
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{Tracks: tracks}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	if _, err := db.DB.Exec(`DELETE FROM tracks WHERE id > 10`); err != nil {
+		t.Fatalf("delete tracks: %v", err)
+	}
+
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat before vacuum: %v", err)
+	}
+
+	if err := db.Vacuum(); err != nil {
+		t.Fatalf("Vacuum: %v", err)
+	}
+
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat after vacuum: %v", err)
+	}
+	if after.Size() >= before.Size() {
+		t.Fatalf("got size %d after vacuum, want smaller than %d before", after.Size(), before.Size())
+	}
+
+	var integrity string
+	if err := db.DB.QueryRow(`PRAGMA integrity_check`).Scan(&integrity); err != nil {
+		t.Fatalf("PRAGMA integrity_check: %v", err)
+	}
+	if integrity != "ok" {
+		t.Fatalf("got integrity_check %q, want \"ok\"", integrity)
+	}
+
+	var count int
+	if err := db.DB.QueryRow(`SELECT COUNT(*) FROM tracks`).Scan(&count); err != nil {
+		t.Fatalf("count tracks: %v", err)
+	}
+	if count != 10 {
+		t.Fatalf("got %d tracks after vacuum, want 10 (vacuum must not change row data)", count)
+	}
+}
+
+func TestVacuumRejectsReadOnlyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "readonly.db")
+	db, err := NewDatabaseManager(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	defer db.Close()
+
+	if err := os.Chmod(path, 0o444); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	defer os.Chmod(path, 0o644)
+
+	if err := db.Vacuum(); err == nil {
+		t.Fatal("Vacuum: want an error against a read-only database file, got nil")
+	}
+}
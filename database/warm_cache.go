@@ -0,0 +1,17 @@
+package database
+
+import "log/slog"
+
+// WarmCache runs each of queries through SearchTracks so its result is
+// already cached before the first real request for it arrives, trading a
+// burst of up-front work (typically at server startup) for lower
+// first-query latency against predictable, popular searches. A query that
+// fails (e.g. shorter than MinQueryLength) is logged and skipped rather
+// than aborting the rest.
+func (sm *SearchManager) WarmCache(queries []string) {
+	for _, q := range queries {
+		if _, err := sm.SearchTracks(q, nil); err != nil {
+			slog.Warn("database: warm cache query failed", "query", q, "error", err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package database
+
+import "testing"
+
+// TestWarmCachePopulatesCacheForListedQueries confirms every query passed
+// to WarmCache reports a cache hit on the very next search, instead of
+// re-scanning the library.
+func TestWarmCachePopulatesCacheForListedQueries(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "p1", Name: "So What", Artist: "Miles Davis"},
+			{PersistentID: "p2", Name: "Take Five", Artist: "Dave Brubeck"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	sm := NewSearchManagerWithLimit(db, DefaultSearchLimit)
+	queries := []string{"Miles Davis", "Brubeck"}
+	sm.WarmCache(queries)
+
+	for _, q := range queries {
+		if _, err := sm.SearchTracks(q, nil); err != nil {
+			t.Fatalf("SearchTracks(%q): %v", q, err)
+		}
+		if !sm.LastMetrics().CacheHit {
+			t.Fatalf("query %q: got no cache hit after WarmCache", q)
+		}
+	}
+}
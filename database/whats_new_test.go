@@ -0,0 +1,60 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetTracksAddedSinceLastRefresh(t *testing.T) {
+	db := newTestDB(t)
+
+	// First refresh: just t1, backdated well before the boundary we'll set
+	// below, simulating a track that was already in the library before the
+	// "since" window we're about to test.
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{{PersistentID: "t1", Name: "Old Track"}},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript (first): %v", err)
+	}
+	if _, err := db.DB.Exec(`UPDATE tracks SET date_added = ? WHERE persistent_id = ?`,
+		"2020-01-01 00:00:00", "t1"); err != nil {
+		t.Fatalf("backdate t1: %v", err)
+	}
+
+	// Force the refresh boundary used by GetTracksAddedSinceLastRefresh to
+	// a known point between t1 and t2, so the test doesn't depend on
+	// sub-second timing.
+	boundary := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	if err := db.SetLastRefreshTime(boundary); err != nil {
+		t.Fatalf("SetLastRefreshTime(boundary): %v", err)
+	}
+
+	// Second refresh: adds t2 (date_added defaults to now, well after
+	// boundary) and sets last_refresh_time again, which pushes boundary
+	// into previous_refresh_time - exactly what GetTracksAddedSinceLastRefresh
+	// should use.
+	if _, err := db.PopulateFromRefreshScript(&RefreshResponse{
+		Tracks: []RefreshTrack{
+			{PersistentID: "t1", Name: "Old Track"},
+			{PersistentID: "t2", Name: "New Track"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript (second): %v", err)
+	}
+
+	prev, err := db.GetPreviousRefreshTime()
+	if err != nil {
+		t.Fatalf("GetPreviousRefreshTime: %v", err)
+	}
+	if !prev.Equal(boundary) {
+		t.Fatalf("GetPreviousRefreshTime = %v, want %v", prev, boundary)
+	}
+
+	added, err := db.GetTracksAddedSinceLastRefresh()
+	if err != nil {
+		t.Fatalf("GetTracksAddedSinceLastRefresh: %v", err)
+	}
+	if len(added) != 1 || added[0].PersistentID != "t2" {
+		t.Fatalf("got %+v, want only t2", added)
+	}
+}
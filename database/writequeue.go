@@ -0,0 +1,53 @@
+package database
+
+import "fmt"
+
+// writeQueueCapacity bounds how many write jobs can be pending before
+// enqueueWrite blocks the caller; generous enough that a burst (e.g.
+// bulk_update touching hundreds of tracks) doesn't stall, without letting
+// an unbounded backlog build up if something downstream wedges.
+const writeQueueCapacity = 256
+
+// writeJob is one unit of work submitted to a DatabaseManager's write
+// queue: run fn and report its result back on resp.
+type writeJob struct {
+	fn   func() error
+	resp chan error
+}
+
+// startWriteQueue launches the single goroutine that serializes every
+// write through m.writeQueue, so concurrent callers (rating changes, play
+// logging, playlist edits from the MCP server) never contend for SQLite's
+// single writer lock. Reads bypass the queue entirely and hit m.DB
+// directly, since WAL lets them proceed concurrently with the writer.
+func (m *DatabaseManager) startWriteQueue() {
+	m.writeQueue = make(chan writeJob, writeQueueCapacity)
+	go func() {
+		for job := range m.writeQueue {
+			job.resp <- withRetry(job.fn)
+		}
+	}()
+}
+
+// stopWriteQueue closes the write queue and blocks until its goroutine has
+// drained any in-flight job, so Close doesn't close the underlying
+// connection out from under a write still running.
+func (m *DatabaseManager) stopWriteQueue() {
+	drain := writeJob{fn: func() error { return nil }, resp: make(chan error, 1)}
+	m.writeQueue <- drain
+	<-drain.resp
+	close(m.writeQueue)
+}
+
+// enqueueWrite submits fn to the write queue and blocks until it has run,
+// returning its result. withRetry still guards each run against a
+// transient lock held by another process (e.g. cmd/migrate); the queue
+// itself only removes contention between goroutines within this process.
+func (m *DatabaseManager) enqueueWrite(fn func() error) error {
+	if m.readOnly {
+		return fmt.Errorf("database: write rejected, database is open read-only")
+	}
+	job := writeJob{fn: fn, resp: make(chan error, 1)}
+	m.writeQueue <- job
+	return <-job.resp
+}
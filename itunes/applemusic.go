@@ -0,0 +1,56 @@
+package itunes
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// AppleMusicLinkKind classifies what an Apple Music share link points at.
+type AppleMusicLinkKind string
+
+const (
+	AppleMusicSong     AppleMusicLinkKind = "song"
+	AppleMusicAlbum    AppleMusicLinkKind = "album"
+	AppleMusicPlaylist AppleMusicLinkKind = "playlist"
+	AppleMusicUnknown  AppleMusicLinkKind = "unknown"
+)
+
+// detectAppleMusicLinkKind inspects a music.apple.com URL's path (and, for
+// album links, its "i" query parameter pointing at a specific track) to
+// tell a song from an album from a playlist.
+func detectAppleMusicLinkKind(u *url.URL) AppleMusicLinkKind {
+	for _, segment := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+		switch segment {
+		case "song":
+			return AppleMusicSong
+		case "playlist":
+			return AppleMusicPlaylist
+		case "album":
+			if u.Query().Get("i") != "" {
+				return AppleMusicSong
+			}
+			return AppleMusicAlbum
+		}
+	}
+	return AppleMusicUnknown
+}
+
+// PlayAppleMusicURL opens an Apple Music catalog share link
+// (https://music.apple.com/...) and plays it, reporting whether the link
+// pointed at a song, album, or playlist.
+func PlayAppleMusicURL(ctx context.Context, rawURL string) (*PlayResult, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || !strings.EqualFold(parsed.Host, "music.apple.com") {
+		return nil, NewITunesError(ErrJXAScript, "play apple music url", fmt.Errorf("not an Apple Music share link: %s", rawURL))
+	}
+	kind := detectAppleMusicLinkKind(parsed)
+
+	result, err := PlayStreamURL(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	result.Message = fmt.Sprintf("opened Apple Music %s link", kind)
+	return result, nil
+}
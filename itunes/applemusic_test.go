@@ -0,0 +1,28 @@
+package itunes
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestDetectAppleMusicLinkKind(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want AppleMusicLinkKind
+	}{
+		{"https://music.apple.com/us/song/some-song/123", AppleMusicSong},
+		{"https://music.apple.com/us/album/some-album/456", AppleMusicAlbum},
+		{"https://music.apple.com/us/album/some-album/456?i=789", AppleMusicSong},
+		{"https://music.apple.com/us/playlist/some-playlist/pl.abc", AppleMusicPlaylist},
+		{"https://music.apple.com/us/artist/some-artist/999", AppleMusicUnknown},
+	}
+	for _, c := range cases {
+		u, err := url.Parse(c.raw)
+		if err != nil {
+			t.Fatalf("url.Parse(%q): %v", c.raw, err)
+		}
+		if got := detectAppleMusicLinkKind(u); got != c.want {
+			t.Errorf("detectAppleMusicLinkKind(%q) = %v, want %v", c.raw, got, c.want)
+		}
+	}
+}
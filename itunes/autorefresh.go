@@ -0,0 +1,58 @@
+package itunes
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// autoRefreshEnvVar names the environment variable holding the staleness
+// threshold, in hours, for MaybeAutoRefresh. Unset or non-positive disables
+// the feature.
+const autoRefreshEnvVar = "ITUNES_AUTO_REFRESH_HOURS"
+
+// autoRefreshTimeout bounds how long the background refresh MaybeAutoRefresh
+// starts is allowed to run. Without this, a stuck osascript invocation
+// (e.g. Apple Music not responding) would never be killed, since the
+// caller passes context.Background() and the goroutine would otherwise
+// block on RefreshLibraryCache forever. A var rather than a const so tests
+// can shorten it instead of waiting out the real duration.
+var autoRefreshTimeout = 15 * time.Minute
+
+// MaybeAutoRefresh kicks off a background library refresh if
+// ITUNES_AUTO_REFRESH_HOURS is set and the last successful refresh is older
+// than that many hours (or has never run). It returns immediately; the
+// refresh itself runs in a goroutine so server startup is never blocked on
+// it.
+func MaybeAutoRefresh(ctx context.Context) {
+	hours, err := strconv.Atoi(os.Getenv(autoRefreshEnvVar))
+	if err != nil || hours <= 0 {
+		return
+	}
+	db, _ := dbHandles()
+	if db == nil {
+		return
+	}
+
+	last, err := db.GetLastRefreshTime()
+	if err != nil {
+		slog.Warn("itunes: auto-refresh: could not read last refresh time", "error", err)
+		return
+	}
+	if !last.IsZero() && time.Since(last) < time.Duration(hours)*time.Hour {
+		return
+	}
+
+	go func() {
+		refreshCtx, cancel := context.WithTimeout(ctx, autoRefreshTimeout)
+		defer cancel()
+		stats, err := RefreshLibraryCache(refreshCtx)
+		if err != nil {
+			slog.Warn("itunes: auto-refresh failed", "error", err)
+			return
+		}
+		slog.Info("itunes: auto-refresh complete", "tracks", stats.TrackCount, "playlists", stats.PlaylistCount, "skipped", stats.SkippedTracks)
+	}()
+}
@@ -0,0 +1,41 @@
+package itunes
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// blockingRunner is a scriptRunner that blocks until ctx is canceled, then
+// returns ctx.Err(), simulating a stuck osascript invocation that only
+// exits once its context's deadline fires.
+type blockingRunner struct{}
+
+func (blockingRunner) Run(ctx context.Context, scriptPath string, args ...string) (string, string, error) {
+	<-ctx.Done()
+	return "", "", ctx.Err()
+}
+
+func TestMaybeAutoRefreshDoesNotLeakGoroutineOnStuckScript(t *testing.T) {
+	withTestDB(t)
+	withFakeRunner(t, blockingRunner{})
+
+	prevTimeout := autoRefreshTimeout
+	autoRefreshTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { autoRefreshTimeout = prevTimeout })
+
+	t.Setenv("ITUNES_AUTO_REFRESH_HOURS", "1")
+
+	before := runtime.NumGoroutine()
+	MaybeAutoRefresh(context.Background())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= before {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("goroutine count stayed above baseline (%d) after autoRefreshTimeout elapsed, want it back down, got %d", before, runtime.NumGoroutine())
+}
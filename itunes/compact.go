@@ -0,0 +1,67 @@
+package itunes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultNowPlayingFormat mirrors GetNowPlayingCompact's documented example:
+// "▶ Artist — Title [1:23/4:56]". Placeholders: {glyph} {artist} {title}
+// {position} {duration}.
+const DefaultNowPlayingFormat = "{glyph} {artist} — {title} [{position}/{duration}]"
+
+// NowPlayingFormat is the template used by GetNowPlayingCompact. It can be
+// overridden programmatically or via ITUNES_NOWPLAYING_FORMAT.
+var NowPlayingFormat = defaultFormatFromEnv()
+
+func defaultFormatFromEnv() string {
+	if v := os.Getenv("ITUNES_NOWPLAYING_FORMAT"); v != "" {
+		return v
+	}
+	return DefaultNowPlayingFormat
+}
+
+func glyphFor(status *NowPlayingStatus) string {
+	switch {
+	case status.IsStreaming && status.State == StatePlaying:
+		return "📡"
+	case status.State == StatePlaying:
+		return "▶"
+	case status.State == StatePaused:
+		return "⏸"
+	default:
+		return "⏹"
+	}
+}
+
+// GetNowPlayingCompact renders the current playback state as a single line
+// suitable for a status bar or shell prompt, using NowPlayingFormat.
+func GetNowPlayingCompact(ctx context.Context) (string, error) {
+	status, err := GetNowPlaying(ctx)
+	if err != nil {
+		return "", err
+	}
+	if status.State == StateStopped && status.Title == "" {
+		return "⏹", nil
+	}
+
+	replacer := strings.NewReplacer(
+		"{glyph}", glyphFor(status),
+		"{artist}", status.Artist,
+		"{title}", status.Title,
+		"{position}", formatDuration(status.Position),
+		"{duration}", formatDuration(status.Duration),
+	)
+	return replacer.Replace(NowPlayingFormat), nil
+}
+
+// formatDuration renders a second count as "m:ss".
+func formatDuration(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	total := int(seconds)
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
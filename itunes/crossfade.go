@@ -0,0 +1,45 @@
+package itunes
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// CrossfadeSettings reflects Apple Music's crossfade preference.
+type CrossfadeSettings struct {
+	Enabled bool `json:"enabled"`
+	Seconds int  `json:"seconds"`
+}
+
+// minCrossfadeSeconds and maxCrossfadeSeconds mirror the range Apple Music
+// exposes in its UI; SetCrossfade rejects anything outside it.
+const (
+	minCrossfadeSeconds = 1
+	maxCrossfadeSeconds = 12
+)
+
+// GetCrossfadeSettings reads Apple Music's current crossfade preference via
+// JXA. If the installed Music version doesn't expose crossfade scripting,
+// ErrUnsupportedFeature is returned before the script even runs so callers
+// can report that distinctly from a script that ran and failed.
+func GetCrossfadeSettings(ctx context.Context) (*CrossfadeSettings, error) {
+	if err := requireFeature(ctx, "crossfade"); err != nil {
+		return nil, err
+	}
+	return runScriptJSON[CrossfadeSettings](ctx, filepath.Join(scriptDir(), "iTunes_Get_Crossfade.js"))
+}
+
+// SetCrossfade enables/disables crossfade and, when enabled, sets its
+// duration in seconds (Apple Music allows roughly 1-12).
+func SetCrossfade(ctx context.Context, enabled bool, seconds int) (*CrossfadeSettings, error) {
+	if err := requireFeature(ctx, "crossfade"); err != nil {
+		return nil, err
+	}
+	if enabled && (seconds < minCrossfadeSeconds || seconds > maxCrossfadeSeconds) {
+		return nil, NewITunesError(ErrJXAScript, "set crossfade", fmt.Errorf("seconds must be between %d and %d, got %d", minCrossfadeSeconds, maxCrossfadeSeconds, seconds))
+	}
+	return runScriptJSON[CrossfadeSettings](ctx, filepath.Join(scriptDir(), "iTunes_Set_Crossfade.js"),
+		strconv.FormatBool(enabled), strconv.Itoa(seconds))
+}
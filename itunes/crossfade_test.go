@@ -0,0 +1,24 @@
+package itunes
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetCrossfadeRejectsOutOfRangeSeconds(t *testing.T) {
+	for _, seconds := range []int{0, -1, 13, 100} {
+		if _, err := SetCrossfade(context.Background(), true, seconds); err == nil {
+			t.Errorf("SetCrossfade(true, %d) = nil error, want a range error", seconds)
+		}
+	}
+}
+
+func TestSetCrossfadeAllowsDisablingRegardlessOfSeconds(t *testing.T) {
+	// Disabling crossfade doesn't need a valid duration; the call may still
+	// fail for other reasons (no osascript in this sandbox), but it must
+	// not fail on range validation specifically.
+	_, err := SetCrossfade(context.Background(), false, 0)
+	if itErr, ok := err.(*ITunesError); ok && itErr.Message == "set crossfade" {
+		t.Errorf("SetCrossfade(false, 0) incorrectly failed range validation: %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package itunes
+
+import "testing"
+
+func TestDecodeOKJSON(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	var p payload
+	if err := decodeOKJSON(`OK:{"name":"hi"}`, &p); err != nil {
+		t.Fatalf("decodeOKJSON: %v", err)
+	}
+	if p.Name != "hi" {
+		t.Fatalf("got %q, want hi", p.Name)
+	}
+
+	if err := decodeOKJSON("ERROR:boom", &p); err == nil {
+		t.Fatal("expected an error for ERROR: output")
+	}
+
+	if err := decodeOKJSON(`OK:{"name":`, &p); err == nil {
+		t.Fatal("expected an error for truncated JSON")
+	}
+}
@@ -0,0 +1,68 @@
+package itunes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// TrackEdit is UpdateTrackMetadata's input: only non-nil fields are
+// changed, in both Apple Music and the local cache.
+type TrackEdit struct {
+	Name   *string `json:"name,omitempty"`
+	Artist *string `json:"artist,omitempty"`
+	Album  *string `json:"album,omitempty"`
+	Genre  *string `json:"genre,omitempty"`
+}
+
+// IsEmpty reports whether edit has no fields set, the condition
+// UpdateTrackMetadata rejects since an edit with nothing to change is
+// almost always a caller mistake.
+func (e TrackEdit) IsEmpty() bool {
+	return e.Name == nil && e.Artist == nil && e.Album == nil && e.Genre == nil
+}
+
+// setTrackMetadataResponse is the OK:<json> payload
+// iTunes_Set_Track_Metadata.js reports back; its field isn't consumed, but
+// decoding through runScriptJSON is what surfaces an ERROR:track not found
+// response as a Go error.
+type setTrackMetadataResponse struct {
+	PersistentID string `json:"persistent_id"`
+}
+
+// UpdateTrackMetadata writes edit's non-nil fields to the track identified
+// by persistentID, first via JXA (so Apple Music's own library reflects the
+// change) and then into the local cache (maintaining artist/album/genre
+// foreign keys, pruning any that become unused, and keeping the tracks FTS
+// index in sync via its update trigger). edit must have at least one field
+// set.
+func UpdateTrackMetadata(ctx context.Context, persistentID string, edit TrackEdit) error {
+	if edit.IsEmpty() {
+		return NewITunesError(ErrNotFound, "update track metadata", fmt.Errorf("no fields provided"))
+	}
+	fieldsJSON, err := json.Marshal(edit)
+	if err != nil {
+		return NewITunesError(ErrJXAScript, "update track metadata", err)
+	}
+	if _, err := runScriptJSON[setTrackMetadataResponse](ctx, filepath.Join(scriptDir(), "iTunes_Set_Track_Metadata.js"), persistentID, string(fieldsJSON)); err != nil {
+		return err
+	}
+
+	db, _ := dbHandles()
+	if db == nil {
+		return NewITunesError(ErrDatabase, "update track metadata", fmt.Errorf("database not initialized"))
+	}
+	dbEdit := database.TrackEdit{Name: edit.Name, Artist: edit.Artist, Album: edit.Album, Genre: edit.Genre}
+	if err := db.UpdateTrackFields(persistentID, dbEdit); err != nil {
+		if errors.Is(err, database.ErrNoTracksFound) {
+			return ErrNoTracksFound
+		}
+		return NewITunesError(ErrDatabase, "update track metadata", err)
+	}
+	ClearSearchCache()
+	return nil
+}
@@ -0,0 +1,69 @@
+package itunes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func TestUpdateTrackMetadataRejectsEmptyEdit(t *testing.T) {
+	withTestDB(t)
+	if err := UpdateTrackMetadata(context.Background(), "p1", TrackEdit{}); err == nil {
+		t.Fatal("expected an error for an edit with no fields set")
+	}
+}
+
+func TestUpdateTrackMetadataMirrorsIntoCache(t *testing.T) {
+	withTestDB(t)
+	if _, err := DBManager.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{{PersistentID: "p1", Name: "Song", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("seed track: %v", err)
+	}
+	withFakeRunner(t, fakeRunner{stdout: `OK:{"persistent_id":"p1"}`})
+
+	newName := "New Song"
+	if err := UpdateTrackMetadata(context.Background(), "p1", TrackEdit{Name: &newName}); err != nil {
+		t.Fatalf("UpdateTrackMetadata: %v", err)
+	}
+
+	track, err := GetTrackByPersistentID("p1")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID: %v", err)
+	}
+	if track.Name != newName {
+		t.Fatalf("got name %q, want %q", track.Name, newName)
+	}
+}
+
+func TestUpdateTrackMetadataClearsSearchCache(t *testing.T) {
+	withTestDB(t)
+	if _, err := DBManager.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{{PersistentID: "p1", Name: "Song", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("seed track: %v", err)
+	}
+	if _, err := SearchManager.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("seed search: %v", err)
+	}
+	if _, err := SearchManager.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("repeat search: %v", err)
+	}
+	if !SearchManager.LastMetrics().CacheHit {
+		t.Fatal("expected the repeat search to hit the cache before UpdateTrackMetadata runs")
+	}
+
+	withFakeRunner(t, fakeRunner{stdout: `OK:{"persistent_id":"p1"}`})
+	newGenre := "Jazz"
+	if err := UpdateTrackMetadata(context.Background(), "p1", TrackEdit{Genre: &newGenre}); err != nil {
+		t.Fatalf("UpdateTrackMetadata: %v", err)
+	}
+
+	if _, err := SearchManager.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("post-write search: %v", err)
+	}
+	if SearchManager.LastMetrics().CacheHit {
+		t.Fatal("expected UpdateTrackMetadata to have cleared the search cache")
+	}
+}
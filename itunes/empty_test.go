@@ -0,0 +1,25 @@
+package itunes
+
+import (
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func TestSearchTracksFromDatabaseEmptyLibrary(t *testing.T) {
+	db, err := database.NewDatabaseManager("file:itunes_empty_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	defer db.Close()
+
+	prevDB, prevSearch := DBManager, SearchManager
+	DBManager = db
+	SearchManager = database.NewSearchManager(db)
+	defer func() { DBManager, SearchManager = prevDB, prevSearch }()
+
+	_, err = SearchTracksFromDatabase("anything", nil)
+	if err != ErrLibraryEmpty {
+		t.Fatalf("got err=%v, want ErrLibraryEmpty", err)
+	}
+}
@@ -0,0 +1,73 @@
+package itunes
+
+import "fmt"
+
+// ErrorKind classifies an ITunesError so callers (and MCP clients) can
+// react differently to, say, a missing database versus a JXA failure.
+type ErrorKind string
+
+const (
+	ErrJXAScript ErrorKind = "jxa_script"
+	ErrDatabase  ErrorKind = "database"
+	ErrNotFound  ErrorKind = "not_found"
+	// ErrTimeout marks a JXA invocation that was killed by context
+	// cancellation/deadline, as distinct from the script itself failing, so
+	// callers can retry or back off rather than surfacing a generic error.
+	ErrTimeout ErrorKind = "timeout"
+	// ErrUnsupportedFeature marks a call gated on a Music app version that
+	// doesn't expose the scriptable property it needs (see version.go),
+	// as distinct from the script running and failing on its own.
+	ErrUnsupportedFeature ErrorKind = "unsupported_feature"
+	// ErrInvalidQuery marks a search rejected for its input shape (e.g. a
+	// query shorter than the configured minimum), as distinct from a
+	// database failure while running an otherwise-valid query.
+	ErrInvalidQuery ErrorKind = "invalid_query"
+)
+
+// ITunesError wraps a lower-level failure with a Kind so it can be handled
+// programmatically instead of by matching error strings.
+type ITunesError struct {
+	Kind    ErrorKind
+	Message string
+	Err     error
+}
+
+func (e *ITunesError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *ITunesError) Unwrap() error {
+	return e.Err
+}
+
+// NewITunesError constructs an ITunesError of the given kind.
+func NewITunesError(kind ErrorKind, message string, err error) *ITunesError {
+	return &ITunesError{Kind: kind, Message: message, Err: err}
+}
+
+// ErrorCode returns a stable, machine-readable string derived from Kind, so
+// MCP clients can branch on failure type (e.g. "database not initialized"
+// vs. "Apple Music not running") instead of matching on Message text.
+func (e *ITunesError) ErrorCode() string {
+	if e.Kind == "" {
+		return "unknown"
+	}
+	return string(e.Kind)
+}
+
+// ErrNoTracksFound is returned by lookups that expect at least one match.
+var ErrNoTracksFound = NewITunesError(ErrNotFound, "no tracks found", nil)
+
+// ErrLibraryEmpty indicates the database has zero cached tracks, as
+// distinct from a specific query simply matching nothing. Callers such as
+// the MCP search handler use this to suggest running refresh_library
+// instead of reporting a generic failure.
+var ErrLibraryEmpty = NewITunesError(ErrNotFound, "library empty, run refresh_library", nil)
+
+// ErrPlaylistNotFound is returned by GetPlaylistTracks when no playlist
+// matches the given persistent ID, as distinct from a playlist that exists
+// but has no tracks.
+var ErrPlaylistNotFound = NewITunesError(ErrNotFound, "playlist not found", nil)
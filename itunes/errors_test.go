@@ -0,0 +1,23 @@
+package itunes
+
+import "testing"
+
+func TestITunesErrorCode(t *testing.T) {
+	cases := []struct {
+		kind ErrorKind
+		want string
+	}{
+		{ErrJXAScript, "jxa_script"},
+		{ErrDatabase, "database"},
+		{ErrNotFound, "not_found"},
+		{ErrTimeout, "timeout"},
+		{ErrUnsupportedFeature, "unsupported_feature"},
+		{"", "unknown"},
+	}
+	for _, c := range cases {
+		err := NewITunesError(c.kind, "boom", nil)
+		if got := err.ErrorCode(); got != c.want {
+			t.Errorf("ErrorCode() for kind %q = %q, want %q", c.kind, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package itunes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/chew-z/itunes.vim/pathutil"
+)
+
+// ExportFormatJSON and ExportFormatCSV are the supported track export
+// formats for WriteTracksToFile and the search --output/--format flag.
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+)
+
+// trackCSVHeader are the columns MarshalTracks writes for CSV output.
+var trackCSVHeader = []string{"persistent_id", "name", "artist", "album", "genre", "rating", "starred"}
+
+// MarshalTracks renders tracks as JSON or CSV depending on format. An
+// empty format defaults to JSON. An empty tracks slice still produces a
+// valid result: "[]" for JSON, or just the header row for CSV.
+func MarshalTracks(tracks []Track, format string) ([]byte, error) {
+	if tracks == nil {
+		tracks = []Track{}
+	}
+	switch format {
+	case "", ExportFormatJSON:
+		return json.MarshalIndent(tracks, "", "  ")
+	case ExportFormatCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(trackCSVHeader); err != nil {
+			return nil, err
+		}
+		for _, t := range tracks {
+			if err := w.Write([]string{
+				t.PersistentID, t.Name, t.Artist, t.Album, t.Genre,
+				strconv.Itoa(t.Rating), strconv.FormatBool(t.Starred),
+			}); err != nil {
+				return nil, err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("itunes: unknown export format %q", format)
+	}
+}
+
+// WriteTracksToFile renders tracks per format and writes them atomically
+// (temp file + rename) to path.
+func WriteTracksToFile(path string, tracks []Track, format string) error {
+	data, err := MarshalTracks(tracks, format)
+	if err != nil {
+		return err
+	}
+	return pathutil.WriteFileAtomic(path, data, 0o644)
+}
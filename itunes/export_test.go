@@ -0,0 +1,49 @@
+package itunes
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMarshalTracksJSONEmptyIsEmptyArray(t *testing.T) {
+	data, err := MarshalTracks(nil, ExportFormatJSON)
+	if err != nil {
+		t.Fatalf("MarshalTracks: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "[]" {
+		t.Fatalf("got %q, want %q", data, "[]")
+	}
+}
+
+func TestMarshalTracksCSVIncludesHeaderAndRows(t *testing.T) {
+	tracks := []Track{
+		{PersistentID: "p1", Name: "Song", Artist: "Artist", Rating: 80, Starred: true},
+	}
+	data, err := MarshalTracks(tracks, ExportFormatCSV)
+	if err != nil {
+		t.Fatalf("MarshalTracks: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2 (header + 1 row)", len(lines))
+	}
+	if !strings.Contains(lines[1], "Song") || !strings.Contains(lines[1], "Artist") {
+		t.Fatalf("row %q missing expected fields", lines[1])
+	}
+}
+
+func TestMarshalTracksUnknownFormat(t *testing.T) {
+	if _, err := MarshalTracks(nil, "xml"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
+
+func TestWriteTracksToFileWritesAtomically(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	tracks := []Track{{PersistentID: "p1", Name: "Song", Artist: "Artist"}}
+
+	if err := WriteTracksToFile(path, tracks, ExportFormatJSON); err != nil {
+		t.Fatalf("WriteTracksToFile: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package itunes
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TrackFields lists every JSON field name search_advanced's fields
+// parameter can project a Track down to.
+var TrackFields = []string{
+	"persistent_id", "name", "artist", "album", "genre", "duration",
+	"rating", "starred", "disliked", "play_count", "last_played",
+	"ranking", "relevance", "track_kind", "is_streaming", "stream_url",
+}
+
+func isKnownTrackField(name string) bool {
+	for _, f := range TrackFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFields returns an error naming the first field in fields that
+// isn't in TrackFields. Callers that want to tolerate unknown fields
+// instead of rejecting the request should skip calling this and rely on
+// ProjectTrack silently dropping them.
+func ValidateFields(fields []string) error {
+	for _, f := range fields {
+		if !isKnownTrackField(f) {
+			return fmt.Errorf("unknown field %q", f)
+		}
+	}
+	return nil
+}
+
+// ProjectTrack whitelists t's JSON representation down to fields. An empty
+// fields returns every field (the default). Unknown field names are
+// silently dropped; use ValidateFields first to reject them instead.
+func ProjectTrack(t Track, fields []string) (map[string]interface{}, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var full map[string]interface{}
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return full, nil
+	}
+	projected := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if v, ok := full[f]; ok {
+			projected[f] = v
+		}
+	}
+	return projected, nil
+}
+
+// ProjectTracks projects every track in tracks; see ProjectTrack.
+func ProjectTracks(tracks []Track, fields []string) ([]map[string]interface{}, error) {
+	projected := make([]map[string]interface{}, len(tracks))
+	for i, t := range tracks {
+		p, err := ProjectTrack(t, fields)
+		if err != nil {
+			return nil, err
+		}
+		projected[i] = p
+	}
+	return projected, nil
+}
@@ -0,0 +1,76 @@
+package itunes
+
+import "testing"
+
+func TestProjectTrackWhitelistsRequestedFields(t *testing.T) {
+	track := Track{PersistentID: "p1", Name: "So What", Artist: "Miles Davis", Album: "Kind of Blue", Rating: 100}
+
+	projected, err := ProjectTrack(track, []string{"name", "artist"})
+	if err != nil {
+		t.Fatalf("ProjectTrack: %v", err)
+	}
+	if len(projected) != 2 || projected["name"] != "So What" || projected["artist"] != "Miles Davis" {
+		t.Fatalf("got %+v, want only name and artist", projected)
+	}
+}
+
+func TestProjectTrackEmptyFieldsReturnsEverything(t *testing.T) {
+	track := Track{PersistentID: "p1", Name: "So What", Artist: "Miles Davis"}
+
+	projected, err := ProjectTrack(track, nil)
+	if err != nil {
+		t.Fatalf("ProjectTrack: %v", err)
+	}
+	if projected["persistent_id"] != "p1" || projected["name"] != "So What" || projected["artist"] != "Miles Davis" {
+		t.Fatalf("got %+v, want every field present", projected)
+	}
+}
+
+func TestProjectTrackIgnoresUnknownFields(t *testing.T) {
+	track := Track{PersistentID: "p1", Name: "So What"}
+
+	projected, err := ProjectTrack(track, []string{"name", "bogus_field"})
+	if err != nil {
+		t.Fatalf("ProjectTrack: %v", err)
+	}
+	if _, ok := projected["bogus_field"]; ok {
+		t.Fatalf("got %+v, want bogus_field silently dropped", projected)
+	}
+	if projected["name"] != "So What" {
+		t.Fatalf("got %+v, want name preserved", projected)
+	}
+}
+
+func TestValidateFieldsRejectsUnknownName(t *testing.T) {
+	if err := ValidateFields([]string{"name", "bogus_field"}); err == nil {
+		t.Fatal("expected an error for an unknown field name")
+	}
+	if err := ValidateFields([]string{"name", "artist"}); err != nil {
+		t.Fatalf("ValidateFields: unexpected error for known fields: %v", err)
+	}
+}
+
+func TestGroupedSearchResultProject(t *testing.T) {
+	withTestDB(t)
+	seedMilesDavisTracks(t)
+
+	result, err := SearchTracksGrouped("Miles Davis", nil, GroupByAlbum)
+	if err != nil {
+		t.Fatalf("SearchTracksGrouped: %v", err)
+	}
+
+	projected, err := result.Project([]string{"name"})
+	if err != nil {
+		t.Fatalf("Project: %v", err)
+	}
+	for _, g := range projected.Groups {
+		for _, track := range g.Tracks {
+			if len(track) != 1 {
+				t.Fatalf("got %+v, want only the name field", track)
+			}
+			if _, ok := track["name"]; !ok {
+				t.Fatalf("got %+v, want a name field", track)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package itunes
+
+import "github.com/chew-z/itunes.vim/database"
+
+// GroupByAlbum and GroupByArtist are the supported SearchTracksGrouped
+// groupings.
+const (
+	GroupByAlbum  = "album"
+	GroupByArtist = "artist"
+)
+
+// TrackGroup is one bucket of a grouped search result, keyed by album or
+// artist name depending on the requested grouping.
+type TrackGroup struct {
+	Key    string  `json:"key"`
+	Tracks []Track `json:"tracks"`
+}
+
+// GroupedSearchResult is SearchTracksGrouped's return shape: the same
+// tracks a plain search would return, bucketed by groupBy while preserving
+// each track's original relative order within its bucket.
+type GroupedSearchResult struct {
+	GroupBy string       `json:"group_by"`
+	Groups  []TrackGroup `json:"groups"`
+}
+
+// SearchTracksGrouped runs SearchTracksFromDatabase and buckets the results
+// by groupBy ("album" or "artist"). An empty or unrecognized groupBy
+// returns a single group containing all tracks in their original order, so
+// callers don't need a separate ungrouped code path.
+func SearchTracksGrouped(query string, filters *database.SearchFilters, groupBy string) (*GroupedSearchResult, error) {
+	tracks, err := SearchTracksFromDatabase(query, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GroupedSearchResult{GroupBy: groupBy}
+	if groupBy != GroupByAlbum && groupBy != GroupByArtist {
+		result.Groups = []TrackGroup{{Tracks: tracks}}
+		return result, nil
+	}
+
+	index := make(map[string]int)
+	for _, t := range tracks {
+		key := t.Album
+		if groupBy == GroupByArtist {
+			key = t.Artist
+		}
+		if i, ok := index[key]; ok {
+			result.Groups[i].Tracks = append(result.Groups[i].Tracks, t)
+			continue
+		}
+		index[key] = len(result.Groups)
+		result.Groups = append(result.Groups, TrackGroup{Key: key, Tracks: []Track{t}})
+	}
+	return result, nil
+}
+
+// ProjectedTrackGroup mirrors TrackGroup with its tracks whitelisted down
+// to a requested set of fields.
+type ProjectedTrackGroup struct {
+	Key    string                   `json:"key"`
+	Tracks []map[string]interface{} `json:"tracks"`
+}
+
+// ProjectedSearchResult mirrors GroupedSearchResult with its tracks
+// whitelisted down to a requested set of fields.
+type ProjectedSearchResult struct {
+	GroupBy string                `json:"group_by"`
+	Groups  []ProjectedTrackGroup `json:"groups"`
+}
+
+// Project whitelists every track in r down to fields; see ProjectTrack. An
+// empty fields returns every field.
+func (r *GroupedSearchResult) Project(fields []string) (*ProjectedSearchResult, error) {
+	out := &ProjectedSearchResult{GroupBy: r.GroupBy, Groups: make([]ProjectedTrackGroup, 0, len(r.Groups))}
+	for _, g := range r.Groups {
+		tracks, err := ProjectTracks(g.Tracks, fields)
+		if err != nil {
+			return nil, err
+		}
+		out.Groups = append(out.Groups, ProjectedTrackGroup{Key: g.Key, Tracks: tracks})
+	}
+	return out, nil
+}
@@ -0,0 +1,57 @@
+package itunes
+
+import (
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func seedMilesDavisTracks(t *testing.T) {
+	t.Helper()
+	_, err := DBManager.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{
+			{PersistentID: "md-1", Name: "So What", Artist: "Miles Davis", Album: "Kind of Blue"},
+			{PersistentID: "md-2", Name: "Freddie Freeloader", Artist: "Miles Davis", Album: "Kind of Blue"},
+			{PersistentID: "md-3", Name: "Nardis", Artist: "Miles Davis", Album: "Someday My Prince Will Come"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("seed tracks: %v", err)
+	}
+}
+
+func TestSearchTracksGroupedByAlbum(t *testing.T) {
+	withTestDB(t)
+	seedMilesDavisTracks(t)
+
+	result, err := SearchTracksGrouped("Miles Davis", nil, GroupByAlbum)
+	if err != nil {
+		t.Fatalf("SearchTracksGrouped: %v", err)
+	}
+	if len(result.Groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(result.Groups))
+	}
+	byKey := make(map[string]int)
+	for _, g := range result.Groups {
+		byKey[g.Key] = len(g.Tracks)
+	}
+	if byKey["Kind of Blue"] != 2 {
+		t.Errorf("Kind of Blue group has %d tracks, want 2", byKey["Kind of Blue"])
+	}
+	if byKey["Someday My Prince Will Come"] != 1 {
+		t.Errorf("Someday My Prince Will Come group has %d tracks, want 1", byKey["Someday My Prince Will Come"])
+	}
+}
+
+func TestSearchTracksGroupedNoGroupByReturnsSingleGroup(t *testing.T) {
+	withTestDB(t)
+	seedMilesDavisTracks(t)
+
+	result, err := SearchTracksGrouped("Miles Davis", nil, "")
+	if err != nil {
+		t.Fatalf("SearchTracksGrouped: %v", err)
+	}
+	if len(result.Groups) != 1 || len(result.Groups[0].Tracks) != 3 {
+		t.Fatalf("got %+v, want a single group of 3 tracks", result.Groups)
+	}
+}
@@ -0,0 +1,55 @@
+package itunes
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// HealthStatus summarizes whether the library cache and the Apple Music
+// bridge are both reachable.
+type HealthStatus struct {
+	DatabaseOK      bool      `json:"database_ok"`
+	SchemaVersion   int       `json:"schema_version"`
+	TrackCount      int       `json:"track_count"`
+	MusicAppOK      bool      `json:"music_app_ok"`
+	MusicAppVersion string    `json:"music_app_version,omitempty"`
+	LastRefreshTime time.Time `json:"last_refresh_time,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+type jxaPingResponse struct {
+	Running bool `json:"running"`
+}
+
+// GetHealth checks the database connection and the Apple Music JXA bridge,
+// returning a best-effort status even if one half is unreachable.
+func GetHealth(ctx context.Context) *HealthStatus {
+	status := &HealthStatus{SchemaVersion: database.SchemaVersion}
+
+	db, _ := dbHandles()
+	if db == nil {
+		status.Error = "database not initialized"
+	} else if err := db.Ping(); err != nil {
+		status.Error = err.Error()
+	} else {
+		status.DatabaseOK = true
+		if stats, err := db.GetStats(); err == nil {
+			status.TrackCount = stats.TrackCount
+		}
+		if last, err := db.GetLastRefreshTime(); err == nil {
+			status.LastRefreshTime = last
+		}
+	}
+
+	if _, err := runScriptJSON[jxaPingResponse](ctx, filepath.Join(scriptDir(), "iTunes_Ping.js")); err == nil {
+		status.MusicAppOK = true
+	}
+	if version, err := GetMusicAppVersion(ctx); err == nil {
+		status.MusicAppVersion = version
+	}
+
+	return status
+}
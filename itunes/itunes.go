@@ -0,0 +1,828 @@
+// Package itunes is the public API consumed by the MCP server and the CLI.
+// It wraps the database package's library cache with the JXA scripts that
+// drive Apple Music/iTunes, translating between the two worlds.
+package itunes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chew-z/itunes.vim/database"
+	"github.com/chew-z/itunes.vim/pathutil"
+)
+
+// DBManager and SearchManager are initialized by InitDatabase and used by
+// every search/lookup function below. dbMu guards them against a concurrent
+// ReopenDatabase swap: writers (InitDatabase/CloseDatabase/ReopenDatabase)
+// take the write lock, readers like SearchTracksFromDatabase take the read
+// lock so in-flight searches finish against a consistent handle instead of
+// racing a reopen.
+var (
+	DBManager     *database.DatabaseManager
+	SearchManager *database.SearchManager
+	dbMu          sync.RWMutex
+	dbPath        string
+)
+
+// dbHandles returns a consistent snapshot of DBManager/SearchManager under
+// dbMu's read lock, so callers don't race a concurrent
+// ReopenDatabase/CloseDatabase swap when reading the package globals. Every
+// accessor below goes through this instead of touching DBManager/
+// SearchManager directly.
+func dbHandles() (*database.DatabaseManager, *database.SearchManager) {
+	dbMu.RLock()
+	defer dbMu.RUnlock()
+	return DBManager, SearchManager
+}
+
+// scriptDir returns the directory containing the bundled JXA scripts, as
+// the relative path "autoload", resolved against the process's working
+// directory.
+func scriptDir() string {
+	return filepath.Join("autoload")
+}
+
+// InitDatabase opens (or creates) the library cache database at path,
+// defaulting via database.ResolveDBPath when empty, and wires up the
+// package-level DBManager/SearchManager.
+func InitDatabase(path string) error {
+	db, err := database.NewDatabaseManager(path)
+	if err != nil {
+		return NewITunesError(ErrDatabase, "init database", err)
+	}
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	DBManager = db
+	SearchManager = database.NewSearchManager(db)
+	dbPath = path
+	return nil
+}
+
+// InitDatabaseReadOnly opens the library cache database at path read-only
+// and skips schema migrations entirely, failing clearly via
+// database.ErrSchemaOutdated if the on-disk schema needs one it can't run.
+// This is for the MCP server, which only ever reads/searches: running it
+// read-only lets it coexist with a concurrent itunes-migrate process
+// against the same file, which InitDatabase's write queue and migrations
+// are not safe to do.
+func InitDatabaseReadOnly(path string) error {
+	db, err := database.NewDatabaseManagerReadOnly(path)
+	if err != nil {
+		return NewITunesError(ErrDatabase, "init database read-only", err)
+	}
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	DBManager = db
+	SearchManager = database.NewSearchManager(db)
+	dbPath = path
+	return nil
+}
+
+// CloseDatabase releases the database connection opened by InitDatabase.
+func CloseDatabase() error {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	if DBManager == nil {
+		return nil
+	}
+	err := DBManager.Close()
+	DBManager = nil
+	SearchManager = nil
+	return err
+}
+
+// ReopenDatabase closes the current DBManager/SearchManager and
+// reinitializes them against the same path InitDatabase was last called
+// with, picking up a replacement DB file (e.g. written by a migrate run in
+// another process) without restarting the MCP server. The swap holds
+// dbMu's write lock, so concurrent SearchTracksFromDatabase calls either
+// complete against the old handle first or block until the new one is in
+// place rather than observing a half-swapped state.
+func ReopenDatabase() error {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+
+	if DBManager == nil {
+		return NewITunesError(ErrDatabase, "reopen database", fmt.Errorf("database not initialized"))
+	}
+	path := dbPath
+	if err := DBManager.Close(); err != nil {
+		return NewITunesError(ErrDatabase, "reopen database", err)
+	}
+	db, err := database.NewDatabaseManager(path)
+	if err != nil {
+		DBManager = nil
+		SearchManager = nil
+		return NewITunesError(ErrDatabase, "reopen database", err)
+	}
+	DBManager = db
+	SearchManager = database.NewSearchManager(db)
+	return nil
+}
+
+// SearchTracksFromDatabase runs a text/filter search against the cache and
+// returns the API Track shape.
+func SearchTracksFromDatabase(query string, filters *database.SearchFilters) ([]Track, error) {
+	db, sm := dbHandles()
+	if sm == nil {
+		return nil, NewITunesError(ErrDatabase, "search tracks", fmt.Errorf("database not initialized"))
+	}
+	rows, err := sm.SearchTracks(query, filters)
+	if errors.Is(err, database.ErrQueryTooShort) {
+		return nil, NewITunesError(ErrInvalidQuery, "search tracks", err)
+	}
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "search tracks", err)
+	}
+	if len(rows) == 0 {
+		if stats, statsErr := db.GetStats(); statsErr == nil && stats.TrackCount == 0 {
+			return nil, ErrLibraryEmpty
+		}
+	}
+	tracks := make([]Track, 0, len(rows))
+	for _, r := range rows {
+		tracks = append(tracks, trackFromDB(r))
+	}
+	return tracks, nil
+}
+
+// SearchTracksWithTimeout is SearchTracksFromDatabase with a latency
+// budget: if the search hasn't finished within timeout, it's cancelled and
+// the partial results found so far are returned alongside ErrSearchTimeout,
+// so a caller (e.g. the MCP server) can't be hung by a pathological query
+// against a very large library.
+func SearchTracksWithTimeout(query string, filters *database.SearchFilters, timeout time.Duration) ([]Track, error) {
+	db, sm := dbHandles()
+	if sm == nil {
+		return nil, NewITunesError(ErrDatabase, "search tracks", fmt.Errorf("database not initialized"))
+	}
+	rows, err := sm.SearchTracksWithTimeout(query, filters, timeout)
+	var timeoutErr *database.SearchTimeoutError
+	if errors.As(err, &timeoutErr) {
+		tracks := make([]Track, 0, len(timeoutErr.Partial))
+		for _, r := range timeoutErr.Partial {
+			tracks = append(tracks, trackFromDB(r))
+		}
+		return tracks, NewITunesError(ErrTimeout, "search tracks", database.ErrSearchTimeout)
+	}
+	if errors.Is(err, database.ErrQueryTooShort) {
+		return nil, NewITunesError(ErrInvalidQuery, "search tracks", err)
+	}
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "search tracks", err)
+	}
+	if len(rows) == 0 {
+		if stats, statsErr := db.GetStats(); statsErr == nil && stats.TrackCount == 0 {
+			return nil, ErrLibraryEmpty
+		}
+	}
+	tracks := make([]Track, 0, len(rows))
+	for _, r := range rows {
+		tracks = append(tracks, trackFromDB(r))
+	}
+	return tracks, nil
+}
+
+// SearchAlbums returns distinct albums matching query/filters instead of
+// individual tracks, for browsing a discography without the per-track
+// noise.
+func SearchAlbums(query string, filters *database.SearchFilters) ([]database.AlbumResult, error) {
+	db, sm := dbHandles()
+	if sm == nil {
+		return nil, NewITunesError(ErrDatabase, "search albums", fmt.Errorf("database not initialized"))
+	}
+	albums, err := sm.SearchAlbums(query, filters)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "search albums", err)
+	}
+	if len(albums) == 0 {
+		if stats, statsErr := db.GetStats(); statsErr == nil && stats.TrackCount == 0 {
+			return nil, ErrLibraryEmpty
+		}
+	}
+	return albums, nil
+}
+
+// SearchArtists returns distinct artists matching query/filters instead of
+// individual tracks; see SearchAlbums.
+func SearchArtists(query string, filters *database.SearchFilters) ([]database.ArtistResult, error) {
+	db, sm := dbHandles()
+	if sm == nil {
+		return nil, NewITunesError(ErrDatabase, "search artists", fmt.Errorf("database not initialized"))
+	}
+	artists, err := sm.SearchArtists(query, filters)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "search artists", err)
+	}
+	if len(artists) == 0 {
+		if stats, statsErr := db.GetStats(); statsErr == nil && stats.TrackCount == 0 {
+			return nil, ErrLibraryEmpty
+		}
+	}
+	return artists, nil
+}
+
+// BulkSetStarred sets the starred flag on every track matching filters,
+// returning how many tracks it touched (or would touch, for dryRun).
+func BulkSetStarred(filters *database.SearchFilters, starred bool, dryRun bool) (*database.BulkUpdateResult, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "bulk set starred", fmt.Errorf("database not initialized"))
+	}
+	result, err := db.BulkSetStarred(filters, starred, dryRun)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "bulk set starred", err)
+	}
+	if !dryRun {
+		ClearSearchCache()
+	}
+	return result, nil
+}
+
+// BulkSetRating sets the rating on every track matching filters, returning
+// how many tracks it touched (or would touch, for dryRun).
+func BulkSetRating(filters *database.SearchFilters, rating int, dryRun bool) (*database.BulkUpdateResult, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "bulk set rating", fmt.Errorf("database not initialized"))
+	}
+	result, err := db.BulkSetRating(filters, rating, dryRun)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "bulk set rating", err)
+	}
+	if !dryRun {
+		ClearSearchCache()
+	}
+	return result, nil
+}
+
+// ClearSearchCache drops every cached search result, so the next search
+// re-reads the database instead of serving a stale hit. Every write path
+// that can change what a search would return (rating/starred/disliked
+// writes, a library refresh, playlist edits) calls this after committing.
+func ClearSearchCache() {
+	_, sm := dbHandles()
+	if sm == nil {
+		return
+	}
+	sm.ClearCache()
+}
+
+// LastSearchMetrics returns the timing/method/cache-hit info recorded by
+// the most recently executed search, for callers (like the MCP layer's
+// debug mode) that want to surface it without threading it through every
+// search function's return value.
+func LastSearchMetrics() database.SearchMetrics {
+	_, sm := dbHandles()
+	if sm == nil {
+		return database.SearchMetrics{}
+	}
+	return sm.LastMetrics()
+}
+
+// GetTrackByPersistentID looks up a single track by its Apple Music
+// persistent ID.
+func GetTrackByPersistentID(persistentID string) (*Track, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get track", fmt.Errorf("database not initialized"))
+	}
+	row, err := db.GetTrackByPersistentID(persistentID)
+	if err != nil {
+		return nil, ErrNoTracksFound
+	}
+	t := trackFromDB(*row)
+	return &t, nil
+}
+
+// RemapTrackPersistentID changes a track's persistent ID from oldID to
+// newID, for the rare case where Apple Music itself reassigns a
+// persistent ID (e.g. after a library rebuild) and the cached row needs
+// to keep resolving under its new one. Returns ErrNoTracksFound if oldID
+// doesn't match a track; returns a database.ErrPersistentIDConflict-
+// wrapping error (check with errors.Is) if newID already belongs to a
+// different track.
+func RemapTrackPersistentID(oldID, newID string) error {
+	db, _ := dbHandles()
+	if db == nil {
+		return NewITunesError(ErrDatabase, "remap persistent id", fmt.Errorf("database not initialized"))
+	}
+	if err := db.RemapTrackPersistentID(oldID, newID); err != nil {
+		if errors.Is(err, database.ErrNoTracksFound) {
+			return ErrNoTracksFound
+		}
+		return NewITunesError(ErrDatabase, "remap persistent id", err)
+	}
+	ClearSearchCache()
+	return nil
+}
+
+// GetPlaylistTracks returns the tracks in the playlist identified by its
+// Apple Music persistent ID.
+func GetPlaylistTracks(playlistPersistentID string) ([]Track, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get playlist tracks", fmt.Errorf("database not initialized"))
+	}
+	playlist, err := db.GetPlaylistByPersistentID(playlistPersistentID)
+	if err != nil {
+		if errors.Is(err, database.ErrPlaylistNotFound) {
+			return nil, ErrPlaylistNotFound
+		}
+		return nil, NewITunesError(ErrNotFound, "playlist not found", err)
+	}
+	rows, err := db.GetPlaylistTracks(playlist.ID)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "get playlist tracks", err)
+	}
+	tracks := make([]Track, 0, len(rows))
+	for _, r := range rows {
+		tracks = append(tracks, trackFromDB(r))
+	}
+	return tracks, nil
+}
+
+// TrackPage is GetPlaylistTracksPage's return shape: the page of tracks
+// actually returned plus Total, the playlist's full track count.
+type TrackPage struct {
+	Tracks []Track `json:"tracks"`
+	Total  int     `json:"total"`
+}
+
+// GetPlaylistTracksPage returns the tracks in the playlist identified by
+// playlistPersistentID, alongside the playlist's total track count.
+// limit <= 0 returns every track, unpaged; limit > 0 returns at most
+// limit tracks starting at offset, so a caller can page through a very
+// large playlist instead of allocating for it all at once.
+func GetPlaylistTracksPage(playlistPersistentID string, limit, offset int) (*TrackPage, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get playlist tracks", fmt.Errorf("database not initialized"))
+	}
+	playlist, err := db.GetPlaylistByPersistentID(playlistPersistentID)
+	if err != nil {
+		if errors.Is(err, database.ErrPlaylistNotFound) {
+			return nil, ErrPlaylistNotFound
+		}
+		return nil, NewITunesError(ErrNotFound, "playlist not found", err)
+	}
+	page, err := db.GetPlaylistTracksPage(playlist.ID, limit, offset)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "get playlist tracks", err)
+	}
+	tracks := make([]Track, 0, len(page.Tracks))
+	for _, r := range page.Tracks {
+		tracks = append(tracks, trackFromDB(r))
+	}
+	return &TrackPage{Tracks: tracks, Total: page.Total}, nil
+}
+
+// ReorderPlaylistTrack moves trackPersistentID to position toPos within the
+// playlist identified by playlistPersistentID.
+func ReorderPlaylistTrack(playlistPersistentID, trackPersistentID string, toPos int) error {
+	db, _ := dbHandles()
+	if db == nil {
+		return NewITunesError(ErrDatabase, "reorder playlist track", fmt.Errorf("database not initialized"))
+	}
+	if err := db.MovePlaylistTrackByPersistentID(playlistPersistentID, trackPersistentID, toPos); err != nil {
+		return NewITunesError(ErrDatabase, "reorder playlist track", err)
+	}
+	return nil
+}
+
+// ListPlaylists returns the user's playlists. smart, when non-nil,
+// restricts the result to only (true) or only (false) smart playlists.
+// includeSpecial, when true, also returns system playlists like "Recently
+// Added" instead of just ordinary user playlists. limit <= 0 returns every
+// matching playlist, unpaged; limit > 0 returns at most limit playlists
+// starting at offset.
+func ListPlaylists(smart *bool, includeSpecial bool, limit, offset int) (*database.PlaylistPage, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "list playlists", fmt.Errorf("database not initialized"))
+	}
+	return db.ListPlaylists(smart, includeSpecial, limit, offset)
+}
+
+// ListPlaylistTree returns every playlist, including folders, nested under
+// their parent playlist.
+func ListPlaylistTree() ([]*database.PlaylistNode, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "list playlist tree", fmt.Errorf("database not initialized"))
+	}
+	return db.ListPlaylistTree()
+}
+
+// ListRadioStations returns every cached station matching filters, with no
+// implicit truncation.
+func ListRadioStations(filters *database.RadioStationFilters) ([]database.RadioStation, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "list stations", fmt.Errorf("database not initialized"))
+	}
+	return db.ListRadioStations(filters)
+}
+
+// SearchStations searches the radio station cache.
+func SearchStations(query string, filters *database.RadioStationFilters, limit int) ([]database.RadioStation, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "search stations", fmt.Errorf("database not initialized"))
+	}
+	return db.SearchRadioStations(query, filters, limit)
+}
+
+// GetRadioStationByID returns a single cached station, or
+// database.ErrStationNotFound if id doesn't match any row.
+func GetRadioStationByID(id int64) (*database.RadioStation, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get station", fmt.Errorf("database not initialized"))
+	}
+	return db.GetRadioStationByID(id)
+}
+
+// FindStationByURL resolves rawURL to a cached station, normalizing it the
+// same way duplicate-detection does, so PlayStreamURL can link a now-playing
+// stream back to its database.RadioStation row.
+func FindStationByURL(rawURL string) (*database.RadioStation, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "find station by url", fmt.Errorf("database not initialized"))
+	}
+	return db.FindStationByURL(rawURL)
+}
+
+// ImportRadioStations bulk-imports stations into the cache.
+func ImportRadioStations(stations []database.RadioStation, opts database.ImportOptions) (*database.ImportResult, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "import stations", fmt.Errorf("database not initialized"))
+	}
+	return db.ImportRadioStations(stations, opts)
+}
+
+// DeleteInactiveStations prunes every cached station marked inactive (e.g.
+// by a URL health check), returning the count removed. dryRun previews the
+// prune without committing it.
+func DeleteInactiveStations(dryRun bool) (int, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return 0, NewITunesError(ErrDatabase, "delete inactive stations", fmt.Errorf("database not initialized"))
+	}
+	n, err := db.DeleteInactiveStations(dryRun)
+	if err != nil {
+		return 0, NewITunesError(ErrDatabase, "delete inactive stations", err)
+	}
+	return n, nil
+}
+
+// ListStationGenres returns every genre with at least one cached station,
+// with counts, most popular first.
+func ListStationGenres() ([]database.GenreCount, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "list station genres", fmt.Errorf("database not initialized"))
+	}
+	return db.ListStationGenres()
+}
+
+// ListTrackKinds returns the distinct track_kind values in the library
+// with counts, most common first, useful for separating podcasts, streams,
+// and music before filtering a search with SearchFilters.Kind.
+func ListTrackKinds() ([]database.KindCount, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "list track kinds", fmt.Errorf("database not initialized"))
+	}
+	return db.ListTrackKinds()
+}
+
+// ExportStations returns every cached station for the export CLI command.
+func ExportStations() ([]database.RadioStation, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "export stations", fmt.Errorf("database not initialized"))
+	}
+	return db.ExportStations()
+}
+
+// GetNowPlaying asks Apple Music for the current playback state via JXA.
+func GetNowPlaying(ctx context.Context) (*NowPlayingStatus, error) {
+	payload, err := runScriptJSON[jxaNowPlaying](ctx, filepath.Join(scriptDir(), "iTunes_NowPlaying.js"))
+	if err != nil {
+		return nil, err
+	}
+	return nowPlayingFromPayload(*payload), nil
+}
+
+// GetNowPlayingPersistentID returns the Apple Music persistent ID of the
+// currently playing track, via a dedicated JXA property rather than the
+// artist/title/album fields GetNowPlaying returns, since those aren't
+// reliable keys for GetTrackByPersistentID or the write-back APIs (titles
+// collide; the persistent ID doesn't).
+func GetNowPlayingPersistentID(ctx context.Context) (string, error) {
+	payload, err := runScriptJSON[jxaNowPlayingPersistentID](ctx, filepath.Join(scriptDir(), "iTunes_NowPlaying_PersistentID.js"))
+	if err != nil {
+		return "", err
+	}
+	return payload.PersistentID, nil
+}
+
+// PlayPlaylistTrack starts playback of the named track within the named
+// playlist via JXA.
+func PlayPlaylistTrack(ctx context.Context, playlist, track string) error {
+	_, err := runScript(ctx, filepath.Join(scriptDir(), "iTunes_Play_Playlist_Track.js"), playlist, track)
+	return err
+}
+
+// PlayPlaylistTrackWithStatus plays track in playlist and returns the
+// resulting now-playing status.
+func PlayPlaylistTrackWithStatus(ctx context.Context, playlist, track string) (*PlayResult, error) {
+	if err := PlayPlaylistTrack(ctx, playlist, track); err != nil {
+		return nil, err
+	}
+	status, err := pollUntilSettled(ctx, GetNowPlaying, settlePollInterval, settleDelay)
+	if err != nil {
+		return &PlayResult{Success: true, Message: "played, but could not confirm now-playing state"}, nil
+	}
+	result := &PlayResult{Success: true, Status: status, Matched: nowPlayingMatches(status, track, "")}
+	if !result.Matched {
+		result.Message = fmt.Sprintf("requested %q, but Apple Music is now playing %q", track, status.Title)
+	}
+	return result, nil
+}
+
+// PlayPlaylist starts or resumes playback of the named playlist as a
+// whole, without selecting a specific track within it, via JXA.
+func PlayPlaylist(ctx context.Context, playlist string) error {
+	_, err := runScript(ctx, filepath.Join(scriptDir(), "iTunes_Play_Playlist_Track.js"), playlist)
+	return err
+}
+
+// PlayPlaylistWithStatus plays playlist and returns the resulting
+// now-playing status.
+func PlayPlaylistWithStatus(ctx context.Context, playlist string) (*PlayResult, error) {
+	if err := PlayPlaylist(ctx, playlist); err != nil {
+		return nil, err
+	}
+	status, err := pollUntilSettled(ctx, GetNowPlaying, settlePollInterval, settleDelay)
+	if err != nil {
+		return &PlayResult{Success: true, Message: "played, but could not confirm now-playing state"}, nil
+	}
+	return &PlayResult{Success: true, Status: status}, nil
+}
+
+// nowPlayingMatches reports whether status looks like the track the caller
+// asked to play. The title comparison is case-insensitive, and an
+// "Unknown" artist on either side is treated as a wildcard since JXA
+// sometimes can't resolve the artist for a track played by name alone.
+func nowPlayingMatches(status *NowPlayingStatus, requestedTitle, requestedArtist string) bool {
+	if status == nil {
+		return false
+	}
+	if !strings.EqualFold(strings.TrimSpace(status.Title), strings.TrimSpace(requestedTitle)) {
+		return false
+	}
+	if requestedArtist == "" || strings.EqualFold(requestedArtist, "Unknown") ||
+		status.Artist == "" || strings.EqualFold(status.Artist, "Unknown") {
+		return true
+	}
+	return strings.EqualFold(strings.TrimSpace(status.Artist), strings.TrimSpace(requestedArtist))
+}
+
+// PlayStreamURL drives Apple Music to play an internet radio/stream URL,
+// normalizing Apple Music share links first.
+func PlayStreamURL(ctx context.Context, rawURL string) (*PlayResult, error) {
+	url, err := NormalizeStreamURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	_, err = runScript(ctx, filepath.Join(scriptDir(), "iTunes_Play_Stream.js"), url)
+	if err != nil {
+		return nil, err
+	}
+	status, err := pollUntilSettled(ctx, GetNowPlaying, settlePollInterval, streamSettleDelay)
+	if err != nil {
+		return &PlayResult{Success: true, Message: "playing stream, but could not confirm now-playing state"}, nil
+	}
+	enrichStreamNowPlaying(ctx, status, url)
+	return &PlayResult{Success: true, Status: status}, nil
+}
+
+// LikeNowPlayingMode selects whether SearchLikeNowPlaying matches on the
+// current track's artist or its album.
+type LikeNowPlayingMode string
+
+const (
+	LikeNowPlayingArtist LikeNowPlayingMode = "artist"
+	LikeNowPlayingAlbum  LikeNowPlayingMode = "album"
+)
+
+// SearchLikeNowPlaying returns other tracks by the same artist (mode
+// "artist") or from the same album (mode "album") as whatever Apple Music
+// is currently playing. It errors clearly when nothing is playing or the
+// current track can't be resolved in the library cache, e.g. because it's
+// an internet radio stream.
+func SearchLikeNowPlaying(ctx context.Context, mode LikeNowPlayingMode) ([]Track, error) {
+	status, err := GetNowPlaying(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return searchLikeNowPlayingStatus(status, mode)
+}
+
+// searchLikeNowPlayingStatus contains SearchLikeNowPlaying's logic given an
+// already-fetched status, split out so it's testable without a live JXA
+// call.
+func searchLikeNowPlayingStatus(status *NowPlayingStatus, mode LikeNowPlayingMode) ([]Track, error) {
+	if status.State != StatePlaying && status.State != StatePaused {
+		return nil, NewITunesError(ErrNotFound, "nothing is currently playing", nil)
+	}
+	if status.IsStreaming || status.Artist == "" {
+		return nil, NewITunesError(ErrNotFound, "current track is a stream and isn't in the library", nil)
+	}
+
+	filters := &database.SearchFilters{Artist: status.Artist}
+	switch mode {
+	case LikeNowPlayingAlbum:
+		if status.Album == "" {
+			return nil, NewITunesError(ErrNotFound, "current track has no album to match against", nil)
+		}
+		filters.Album = status.Album
+	case LikeNowPlayingArtist:
+		// filters.Artist already set above.
+	default:
+		return nil, NewITunesError(ErrJXAScript, fmt.Sprintf("unknown mode %q, want \"artist\" or \"album\"", mode), nil)
+	}
+
+	tracks, err := SearchTracksFromDatabase("", filters)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Track, 0, len(tracks))
+	for _, t := range tracks {
+		if t.Name == status.Title && t.Artist == status.Artist {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// scriptTrack mirrors the flat per-track JSON entry written by
+// iTunes_Search2_fzf.js: {id, name, album, collection, artist, downloaded,
+// date_added}, where "collection" is the playlist the track was scanned
+// from, "downloaded" reflects whether the script could resolve a local file
+// location for the track, and "date_added" is Apple Music's addedDate as an
+// ISO 8601 string (JXA's JSON.stringify renders JS Date values that way).
+type scriptTrack struct {
+	ID         json.Number `json:"id"`
+	Name       string      `json:"name"`
+	Album      string      `json:"album"`
+	Collection string      `json:"collection"`
+	Artist     string      `json:"artist"`
+	Downloaded bool        `json:"downloaded"`
+	DateAdded  string      `json:"date_added"`
+}
+
+// refreshResponseFromScriptTracks groups a flat scriptTrack list into the
+// database.RefreshResponse shape PopulateFromRefreshScript expects.
+func refreshResponseFromScriptTracks(tracks []scriptTrack) *database.RefreshResponse {
+	resp := &database.RefreshResponse{}
+	playlistTracks := map[string][]string{}
+	for _, st := range tracks {
+		persistentID := st.ID.String()
+		dateAdded, _ := time.Parse(time.RFC3339, st.DateAdded)
+		resp.Tracks = append(resp.Tracks, database.RefreshTrack{
+			PersistentID: persistentID,
+			Name:         st.Name,
+			Artist:       st.Artist,
+			Album:        st.Album,
+			Downloaded:   st.Downloaded,
+			DateAdded:    dateAdded,
+		})
+		playlistTracks[st.Collection] = append(playlistTracks[st.Collection], persistentID)
+	}
+	for name, ids := range playlistTracks {
+		resp.Playlists = append(resp.Playlists, database.RefreshPlaylist{
+			PersistentID: "playlist-" + name,
+			Name:         name,
+			TrackIDs:     ids,
+		})
+	}
+	return resp
+}
+
+// RefreshLibraryCache runs the JXA library-scan script and repopulates the
+// database from its output, returning stats including how many tracks were
+// skipped so callers (the refresh_library MCP tool, the refresh CLI
+// command) can report it.
+func RefreshLibraryCache(ctx context.Context) (*RefreshResult, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "refresh library", fmt.Errorf("database not initialized"))
+	}
+	out, err := runScript(ctx, filepath.Join(scriptDir(), "iTunes_Search2_fzf.js"), "Online")
+	if err != nil {
+		return nil, err
+	}
+
+	var cachePath string
+	if cacheDir, cacheErr := pathutil.ResolveCacheDir(""); cacheErr == nil {
+		cachePath = filepath.Join(cacheDir, "last-refresh.json")
+		_ = os.WriteFile(cachePath, []byte(out), 0o644)
+	}
+
+	var tracks []scriptTrack
+	if err := json.Unmarshal([]byte(out), &tracks); err != nil {
+		return nil, NewITunesError(ErrJXAScript, "parse refresh output", fmt.Errorf("%w (raw: %q)", err, truncate(out, 120)))
+	}
+
+	stats, err := db.PopulateFromRefreshScript(refreshResponseFromScriptTracks(tracks))
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "refresh library", err)
+	}
+	ClearSearchCache()
+	return &RefreshResult{
+		RefreshStats: RefreshStats{
+			TrackCount:     stats.TrackCount,
+			PlaylistCount:  stats.PlaylistCount,
+			SkippedTracks:  stats.SkippedTracks,
+			ProcessingTime: stats.ProcessingTime,
+		},
+		CachePath: cachePath,
+	}, nil
+}
+
+// scriptPlaylist is the shape iTunes_Refresh_Playlists.js reports for a
+// single playlist, used by RefreshPlaylistsOnly.
+type scriptPlaylist struct {
+	PersistentID string   `json:"persistent_id"`
+	Name         string   `json:"name"`
+	SpecialKind  string   `json:"special_kind"`
+	Smart        bool     `json:"smart"`
+	TrackIDs     []string `json:"track_ids"`
+}
+
+// RefreshPlaylistsOnly reconciles playlist definitions and membership from
+// a lighter JXA script that skips the full per-track metadata scan
+// RefreshLibraryCache does. It's much faster for the common case of only
+// having changed which tracks are in which playlist, at the cost of not
+// picking up new/changed track metadata (run RefreshLibraryCache for that).
+func RefreshPlaylistsOnly(ctx context.Context) (*RefreshResult, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "refresh playlists", fmt.Errorf("database not initialized"))
+	}
+	out, err := runScript(ctx, filepath.Join(scriptDir(), "iTunes_Refresh_Playlists.js"))
+	if err != nil {
+		return nil, err
+	}
+	var scriptPlaylists []scriptPlaylist
+	if err := decodeOKJSON(out, &scriptPlaylists); err != nil {
+		return nil, err
+	}
+
+	playlists := make([]database.RefreshPlaylist, 0, len(scriptPlaylists))
+	for _, sp := range scriptPlaylists {
+		playlists = append(playlists, database.RefreshPlaylist{
+			PersistentID: sp.PersistentID,
+			Name:         sp.Name,
+			SpecialKind:  sp.SpecialKind,
+			Smart:        sp.Smart,
+			TrackIDs:     sp.TrackIDs,
+		})
+	}
+
+	stats, err := db.SyncPlaylistsFromRefresh(playlists)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "refresh playlists", err)
+	}
+	ClearSearchCache()
+	return &RefreshResult{
+		RefreshStats: RefreshStats{
+			PlaylistCount:  stats.PlaylistCount,
+			ProcessingTime: stats.ProcessingTime,
+		},
+	}, nil
+}
+
+// RefreshLibrary is a deprecated, error-only wrapper around
+// RefreshLibraryCache for callers that only care whether the refresh
+// succeeded.
+//
+// Deprecated: use RefreshLibraryCache and inspect the returned
+// *RefreshResult.
+func RefreshLibrary(ctx context.Context) error {
+	_, err := RefreshLibraryCache(ctx)
+	return err
+}
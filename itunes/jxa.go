@@ -0,0 +1,124 @@
+package itunes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// scriptSemaphore bounds how many osascript processes runScript will
+// launch at once, so a burst of MCP calls (e.g. a client polling
+// now_playing_compact while also searching) doesn't overload Apple Music's
+// scripting bridge with concurrent invocations. Overridable via
+// ITUNES_MAX_CONCURRENT_SCRIPTS for testing or heavier workloads.
+var scriptSemaphore = make(chan struct{}, maxConcurrentScriptsFromEnv())
+
+func maxConcurrentScriptsFromEnv() int {
+	if v := os.Getenv("ITUNES_MAX_CONCURRENT_SCRIPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 3
+}
+
+// scriptRunner abstracts the actual process invocation behind runScript so
+// tests can swap in a fake returning canned output, exercising the
+// OK:/ERROR: parsing and streaming-vs-local branching without Apple Music
+// or osascript present.
+type scriptRunner interface {
+	Run(ctx context.Context, scriptPath string, args ...string) (stdout, stderr string, err error)
+}
+
+// runner is the scriptRunner used by runScript. Tests reassign it (and
+// restore the original in a defer/t.Cleanup) rather than calling osascript.
+var runner scriptRunner = osascriptRunner{}
+
+// osascriptRunner is the default scriptRunner, shelling out to osascript.
+type osascriptRunner struct{}
+
+func (osascriptRunner) Run(ctx context.Context, scriptPath string, args ...string) (string, string, error) {
+	cmdArgs := append([]string{"-l", "JavaScript", scriptPath}, args...)
+	cmd := exec.CommandContext(ctx, "osascript", cmdArgs...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+// runScript executes a JXA (JavaScript for Automation) script file via the
+// configured scriptRunner, passing args as positional script arguments,
+// and returns its trimmed stdout. Failures are wrapped as an ErrJXAScript
+// so callers can tell a scripting failure apart from a database error; a
+// run cut short by ctx's deadline, or a wait for a slot ended by ctx, is
+// wrapped as an ErrTimeout instead.
+// Concurrent calls beyond maxConcurrentScriptsFromEnv queue until a slot
+// frees up, or until ctx is canceled.
+func runScript(ctx context.Context, scriptPath string, args ...string) (string, error) {
+	select {
+	case scriptSemaphore <- struct{}{}:
+		defer func() { <-scriptSemaphore }()
+	case <-ctx.Done():
+		return "", NewITunesError(ErrTimeout, fmt.Sprintf("run %s timed out waiting for a free slot", scriptPath), ctx.Err())
+	}
+
+	stdout, stderr, err := runner.Run(ctx, scriptPath, args...)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", NewITunesError(ErrTimeout, fmt.Sprintf("run %s timed out", scriptPath), ctx.Err())
+		}
+		return "", NewITunesError(ErrJXAScript, fmt.Sprintf("run %s", scriptPath), fmt.Errorf("%w: %s", err, stderr))
+	}
+
+	out := strings.TrimSpace(stdout)
+	if out == "" {
+		return "", NewITunesError(ErrJXAScript, fmt.Sprintf("run %s", scriptPath), fmt.Errorf("empty output (stderr: %s)", truncate(stderr, 200)))
+	}
+	return out, nil
+}
+
+// decodeOKJSON parses a JXA script's "OK:<json>"/"ERROR:<message>" output
+// convention into out, used both by runScriptJSON and by scripts that parse
+// their own raw output directly (e.g. for unit tests).
+func decodeOKJSON(raw string, out interface{}) error {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "ERROR:") {
+		return NewITunesError(ErrJXAScript, strings.TrimSpace(strings.TrimPrefix(raw, "ERROR:")), nil)
+	}
+	raw = strings.TrimPrefix(raw, "OK:")
+	if err := json.Unmarshal([]byte(raw), out); err != nil {
+		return NewITunesError(ErrJXAScript, "parse script output", fmt.Errorf("%w (raw: %q)", err, truncate(raw, 120)))
+	}
+	return nil
+}
+
+// runScriptJSON runs scriptPath via runScript and decodes its
+// "OK:<json>"/"ERROR:<message>" output into a T, centralizing the
+// exec/parse boilerplate that GetNowPlaying, the health ping, and similar
+// JXA-backed calls would otherwise each repeat.
+func runScriptJSON[T any](ctx context.Context, scriptPath string, args ...string) (*T, error) {
+	raw, err := runScript(ctx, scriptPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	var out T
+	if err := decodeOKJSON(raw, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,29 @@
+package itunes
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunScriptTimeoutIsErrTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond) // ensure the deadline has definitely passed
+
+	_, err := runScript(ctx, "autoload/iTunes_Ping.js")
+	itErr, ok := err.(*ITunesError)
+	if !ok {
+		t.Fatalf("got %T, want *ITunesError", err)
+	}
+	if itErr.Kind != ErrTimeout {
+		t.Fatalf("got Kind=%v, want ErrTimeout", itErr.Kind)
+	}
+}
+
+func TestParseNowPlayingEmptyOutput(t *testing.T) {
+	_, err := parseNowPlaying("")
+	if err == nil {
+		t.Fatal("expected an error for empty now-playing output")
+	}
+}
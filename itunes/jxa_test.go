@@ -0,0 +1,79 @@
+package itunes
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunScriptRespectsConcurrencyLimit(t *testing.T) {
+	prev := scriptSemaphore
+	scriptSemaphore = make(chan struct{}, 1)
+	defer func() { scriptSemaphore = prev }()
+
+	scriptSemaphore <- struct{}{} // occupy the only slot
+	defer func() { <-scriptSemaphore }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := runScript(ctx, "nonexistent-script.js")
+	if err == nil {
+		t.Fatal("expected an error when the slot never frees before ctx deadline")
+	}
+}
+
+// slowCountingRunner is a scriptRunner that tracks how many calls are
+// in-flight concurrently (via inFlight/maxInFlight), sleeping for delay on
+// each call so callers racing ahead of the semaphore would overlap if the
+// cap weren't enforced.
+type slowCountingRunner struct {
+	delay       time.Duration
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (r *slowCountingRunner) Run(ctx context.Context, scriptPath string, args ...string) (string, string, error) {
+	n := atomic.AddInt32(&r.inFlight, 1)
+	for {
+		max := atomic.LoadInt32(&r.maxInFlight)
+		if n <= max || atomic.CompareAndSwapInt32(&r.maxInFlight, max, n) {
+			break
+		}
+	}
+	time.Sleep(r.delay)
+	atomic.AddInt32(&r.inFlight, -1)
+	return "OK:{}", "", nil
+}
+
+// TestRunScriptEnforcesConcurrencyCeiling launches many more concurrent
+// runScript calls than the configured cap and asserts the fake runner
+// never observes more than cap calls in flight at once.
+func TestRunScriptEnforcesConcurrencyCeiling(t *testing.T) {
+	prev := scriptSemaphore
+	const limit = 3
+	scriptSemaphore = make(chan struct{}, limit)
+	defer func() { scriptSemaphore = prev }()
+
+	r := &slowCountingRunner{delay: 20 * time.Millisecond}
+	withFakeRunner(t, r)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := runScript(context.Background(), "fake-script.js"); err != nil {
+				t.Errorf("runScript: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&r.maxInFlight); got > limit {
+		t.Fatalf("got max %d concurrent runs, want at most %d", got, limit)
+	}
+}
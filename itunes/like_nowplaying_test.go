@@ -0,0 +1,60 @@
+package itunes
+
+import (
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func withTestDB(t *testing.T) *database.DatabaseManager {
+	t.Helper()
+	db, err := database.NewDatabaseManager("file:itunes_like_nowplaying_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("NewDatabaseManager: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	prevDB, prevSearch := DBManager, SearchManager
+	DBManager = db
+	SearchManager = database.NewSearchManager(db)
+	t.Cleanup(func() { DBManager, SearchManager = prevDB, prevSearch })
+	return db
+}
+
+func TestSearchLikeNowPlayingByArtist(t *testing.T) {
+	db := withTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{
+			{PersistentID: "p1", Name: "Current Song", Artist: "Aphex Twin", Album: "Selected Ambient Works"},
+			{PersistentID: "p2", Name: "Other Song", Artist: "Aphex Twin", Album: "Drukqs"},
+			{PersistentID: "p3", Name: "Unrelated", Artist: "Someone Else", Album: "Other"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+
+	status := &NowPlayingStatus{State: StatePlaying, Artist: "Aphex Twin", Title: "Current Song", Album: "Selected Ambient Works"}
+	tracks, err := searchLikeNowPlayingStatus(status, LikeNowPlayingArtist)
+	if err != nil {
+		t.Fatalf("searchLikeNowPlayingStatus: %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].Name != "Other Song" {
+		t.Fatalf("got %+v, want only Other Song", tracks)
+	}
+}
+
+func TestSearchLikeNowPlayingNothingPlaying(t *testing.T) {
+	withTestDB(t)
+	status := &NowPlayingStatus{State: StateStopped}
+	if _, err := searchLikeNowPlayingStatus(status, LikeNowPlayingArtist); err == nil {
+		t.Fatal("expected an error when nothing is playing")
+	}
+}
+
+func TestSearchLikeNowPlayingStream(t *testing.T) {
+	withTestDB(t)
+	status := &NowPlayingStatus{State: StatePlaying, IsStreaming: true, Artist: "Radio Host"}
+	if _, err := searchLikeNowPlayingStatus(status, LikeNowPlayingArtist); err == nil {
+		t.Fatal("expected an error for a streaming track")
+	}
+}
@@ -0,0 +1,44 @@
+package itunes
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// lovedDislikedResponse is the OK:<json> payload iTunes_Set_Loved.js and
+// iTunes_Set_Disliked.js report back; its fields aren't consumed, but
+// decoding through runScriptJSON is what surfaces an ERROR:track not found
+// response as a Go error.
+type lovedDislikedResponse struct {
+	PersistentID string `json:"persistent_id"`
+}
+
+// SetTrackLoved sets/clears Apple Music's "loved" state for a track by
+// persistent ID. Loved isn't cached locally (unlike Disliked,
+// which backs the search_advanced disliked filter), so this only talks to
+// Apple Music via JXA.
+func SetTrackLoved(ctx context.Context, persistentID string, loved bool) error {
+	_, err := runScriptJSON[lovedDislikedResponse](ctx, filepath.Join(scriptDir(), "iTunes_Set_Loved.js"), persistentID, strconv.FormatBool(loved))
+	return err
+}
+
+// SetTrackDisliked sets/clears Apple Music's "disliked" state for a track
+// by persistent ID via JXA, and mirrors it into the local cache so
+// SearchFilters.Disliked reflects it immediately rather than after the
+// next library refresh.
+func SetTrackDisliked(ctx context.Context, persistentID string, disliked bool) error {
+	if _, err := runScriptJSON[lovedDislikedResponse](ctx, filepath.Join(scriptDir(), "iTunes_Set_Disliked.js"), persistentID, strconv.FormatBool(disliked)); err != nil {
+		return err
+	}
+	db, _ := dbHandles()
+	if db == nil {
+		return NewITunesError(ErrDatabase, "set track disliked", fmt.Errorf("database not initialized"))
+	}
+	if err := db.SetTrackDisliked(persistentID, disliked); err != nil {
+		return NewITunesError(ErrDatabase, "set track disliked", err)
+	}
+	ClearSearchCache()
+	return nil
+}
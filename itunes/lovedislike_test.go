@@ -0,0 +1,69 @@
+package itunes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func TestSetTrackDislikedMirrorsIntoCache(t *testing.T) {
+	withTestDB(t)
+	if _, err := DBManager.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{{PersistentID: "p1", Name: "Song", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("seed track: %v", err)
+	}
+	withFakeRunner(t, fakeRunner{stdout: `OK:{"persistent_id":"p1","disliked":true}`})
+
+	if err := SetTrackDisliked(context.Background(), "p1", true); err != nil {
+		t.Fatalf("SetTrackDisliked: %v", err)
+	}
+
+	track, err := GetTrackByPersistentID("p1")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID: %v", err)
+	}
+	if !track.Disliked {
+		t.Fatal("expected cached Disliked = true after SetTrackDisliked")
+	}
+}
+
+func TestSetTrackDislikedClearsSearchCache(t *testing.T) {
+	withTestDB(t)
+	if _, err := DBManager.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{{PersistentID: "p1", Name: "Song", Artist: "Artist"}},
+	}); err != nil {
+		t.Fatalf("seed track: %v", err)
+	}
+	if _, err := SearchManager.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("seed search: %v", err)
+	}
+	if _, err := SearchManager.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("repeat search: %v", err)
+	}
+	if !SearchManager.LastMetrics().CacheHit {
+		t.Fatal("expected the repeat search to hit the cache before SetTrackDisliked runs")
+	}
+
+	withFakeRunner(t, fakeRunner{stdout: `OK:{"persistent_id":"p1","disliked":true}`})
+	if err := SetTrackDisliked(context.Background(), "p1", true); err != nil {
+		t.Fatalf("SetTrackDisliked: %v", err)
+	}
+
+	if _, err := SearchManager.SearchTracks("Song", nil); err != nil {
+		t.Fatalf("post-write search: %v", err)
+	}
+	if SearchManager.LastMetrics().CacheHit {
+		t.Fatal("expected SetTrackDisliked to have cleared the search cache")
+	}
+}
+
+func TestSetTrackLovedDoesNotTouchCache(t *testing.T) {
+	withTestDB(t)
+	withFakeRunner(t, fakeRunner{stdout: `OK:{"persistent_id":"p1","loved":true}`})
+
+	if err := SetTrackLoved(context.Background(), "p1", true); err != nil {
+		t.Fatalf("SetTrackLoved: %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package itunes
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+)
+
+// GetLyrics runs a JXA script to read the lyrics of the track identified by
+// persistentID. A track with no lyrics (or a streaming track, which never
+// has any) returns an empty string with a nil error rather than an error.
+func GetLyrics(ctx context.Context, persistentID string) (string, error) {
+	return getLyrics(ctx, persistentID)
+}
+
+// GetCurrentLyrics is a convenience wrapper for the now-playing track.
+func GetCurrentLyrics(ctx context.Context) (string, error) {
+	return getLyrics(ctx, "")
+}
+
+func getLyrics(ctx context.Context, persistentID string) (string, error) {
+	out, err := runScript(ctx, filepath.Join(scriptDir(), "iTunes_Get_Lyrics.js"), persistentID)
+	if err != nil {
+		return "", err
+	}
+	out = strings.TrimSpace(out)
+	if strings.HasPrefix(out, "ERROR:") {
+		return "", NewITunesError(ErrJXAScript, strings.TrimPrefix(out, "ERROR:"), nil)
+	}
+	return strings.TrimPrefix(out, "OK:"), nil
+}
@@ -0,0 +1,26 @@
+package itunes
+
+import "testing"
+
+func TestNowPlayingMatches(t *testing.T) {
+	cases := []struct {
+		name            string
+		status          *NowPlayingStatus
+		requestedTitle  string
+		requestedArtist string
+		want            bool
+	}{
+		{"exact match", &NowPlayingStatus{Title: "Song", Artist: "Artist"}, "Song", "Artist", true},
+		{"case insensitive", &NowPlayingStatus{Title: "song", Artist: "artist"}, "Song", "Artist", true},
+		{"title mismatch", &NowPlayingStatus{Title: "Other Song", Artist: "Artist"}, "Song", "Artist", false},
+		{"artist mismatch", &NowPlayingStatus{Title: "Song", Artist: "Other Artist"}, "Song", "Artist", false},
+		{"unknown artist on status is a wildcard", &NowPlayingStatus{Title: "Song", Artist: "Unknown"}, "Song", "Artist", true},
+		{"unknown requested artist is a wildcard", &NowPlayingStatus{Title: "Song", Artist: "Artist"}, "Song", "Unknown", true},
+		{"nil status", nil, "Song", "Artist", false},
+	}
+	for _, c := range cases {
+		if got := nowPlayingMatches(c.status, c.requestedTitle, c.requestedArtist); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
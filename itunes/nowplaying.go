@@ -0,0 +1,48 @@
+package itunes
+
+// jxaNowPlayingPersistentID is the wire shape written by the JXA
+// now-playing-persistent-id script.
+type jxaNowPlayingPersistentID struct {
+	PersistentID string `json:"persistent_id"`
+}
+
+// jxaNowPlaying is the wire shape written by the JXA now-playing script.
+type jxaNowPlaying struct {
+	State    string  `json:"state"`
+	Artist   string  `json:"artist"`
+	Title    string  `json:"title"`
+	Album    string  `json:"album"`
+	Position float64 `json:"position"`
+	Duration float64 `json:"duration"`
+	Stream   bool    `json:"stream"`
+}
+
+// parseNowPlaying decodes a JXA script's stdout into a NowPlayingStatus. The
+// script may prefix its payload with "OK:" or "ERROR:" to distinguish a
+// clean JSON payload from a human-readable failure message.
+func parseNowPlaying(raw string) (*NowPlayingStatus, error) {
+	var payload jxaNowPlaying
+	if err := decodeOKJSON(raw, &payload); err != nil {
+		return nil, err
+	}
+	return nowPlayingFromPayload(payload), nil
+}
+
+func nowPlayingFromPayload(payload jxaNowPlaying) *NowPlayingStatus {
+	state := PlaybackState(payload.State)
+	switch state {
+	case StatePlaying, StatePaused, StateStopped:
+	default:
+		state = StateStopped
+	}
+
+	return &NowPlayingStatus{
+		State:       state,
+		Artist:      payload.Artist,
+		Title:       payload.Title,
+		Album:       payload.Album,
+		Position:    payload.Position,
+		Duration:    payload.Duration,
+		IsStreaming: payload.Stream,
+	}
+}
@@ -0,0 +1,35 @@
+package itunes
+
+import "fmt"
+
+// OptimizeResult reports a database's on-disk size before and after
+// OptimizeDatabase reclaimed space, so a caller can see whether running it
+// was worth the exclusive lock it takes.
+type OptimizeResult struct {
+	BeforeBytes int64 `json:"before_bytes"`
+	AfterBytes  int64 `json:"after_bytes"`
+}
+
+// OptimizeDatabase runs VACUUM then ANALYZE against the library cache,
+// reclaiming space freed by deletes (rating changes, pruned unused rows, a
+// migration) and refreshing the query planner's statistics. It takes an
+// exclusive lock on the database for its duration, so callers should avoid
+// running it concurrently with a refresh or heavy write traffic.
+func OptimizeDatabase() (*OptimizeResult, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "optimize database", fmt.Errorf("database not initialized"))
+	}
+	before, err := db.GetStats()
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "optimize database", err)
+	}
+	if err := db.Vacuum(); err != nil {
+		return nil, NewITunesError(ErrDatabase, "optimize database", err)
+	}
+	after, err := db.GetStats()
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "optimize database", err)
+	}
+	return &OptimizeResult{BeforeBytes: before.SizeBytes, AfterBytes: after.SizeBytes}, nil
+}
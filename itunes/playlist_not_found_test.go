@@ -0,0 +1,35 @@
+package itunes
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func TestGetPlaylistTracksDistinguishesMissingFromEmpty(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "library.db")
+	if err := InitDatabase(dbPath); err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	t.Cleanup(func() { _ = CloseDatabase() })
+
+	if _, err := DBManager.PopulateFromRefreshScript(&database.RefreshResponse{
+		Playlists: []database.RefreshPlaylist{{PersistentID: "pl1", Name: "Empty"}},
+	}); err != nil {
+		t.Fatalf("seed empty playlist: %v", err)
+	}
+
+	if _, err := GetPlaylistTracks("does-not-exist"); !errors.Is(err, ErrPlaylistNotFound) {
+		t.Fatalf("missing playlist: got %v, want ErrPlaylistNotFound", err)
+	}
+
+	tracks, err := GetPlaylistTracks("pl1")
+	if err != nil {
+		t.Fatalf("empty playlist: unexpected error %v", err)
+	}
+	if len(tracks) != 0 {
+		t.Fatalf("empty playlist: got %d tracks, want 0", len(tracks))
+	}
+}
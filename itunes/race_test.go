@@ -0,0 +1,45 @@
+package itunes
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// TestConcurrentSearchAndReopen exercises SearchTracksFromDatabase and
+// ReopenDatabase from separate goroutines simultaneously. It doesn't assert
+// on call outcomes (a search racing a reopen may legitimately error) - its
+// purpose is to give `go test -race` something to catch if dbHandles ever
+// stops being the only way accessors read DBManager/SearchManager.
+func TestConcurrentSearchAndReopen(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "library.db")
+	if err := InitDatabase(dbPath); err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	t.Cleanup(func() { _ = CloseDatabase() })
+
+	if _, err := DBManager.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{{PersistentID: "race-1", Name: "Race Track", Artist: "Racer"}},
+	}); err != nil {
+		t.Fatalf("seed track: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = SearchTracksFromDatabase("Race", nil)
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = ReopenDatabase()
+		}()
+	}
+	wg.Wait()
+}
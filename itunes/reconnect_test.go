@@ -0,0 +1,62 @@
+package itunes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// TestReopenDatabasePicksUpReplacedFile simulates another process (e.g.
+// itunes-migrate) replacing the on-disk DB file out from under a
+// long-running server: ReopenDatabase should start serving the new file's
+// data without a process restart.
+func TestReopenDatabasePicksUpReplacedFile(t *testing.T) {
+	dir := t.TempDir()
+	livePath := filepath.Join(dir, "library.db")
+
+	if err := InitDatabase(livePath); err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	t.Cleanup(func() { _ = CloseDatabase() })
+
+	if _, err := DBManager.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{{PersistentID: "old-1", Name: "Old Song", Artist: "Old Artist"}},
+	}); err != nil {
+		t.Fatalf("seed old track: %v", err)
+	}
+
+	replacementPath := filepath.Join(dir, "replacement.db")
+	replacement, err := database.NewDatabaseManager(replacementPath)
+	if err != nil {
+		t.Fatalf("NewDatabaseManager(replacement): %v", err)
+	}
+	if _, err := replacement.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{{PersistentID: "new-1", Name: "New Song", Artist: "New Artist"}},
+	}); err != nil {
+		t.Fatalf("seed new track: %v", err)
+	}
+	if err := replacement.Close(); err != nil {
+		t.Fatalf("close replacement: %v", err)
+	}
+
+	if err := os.Rename(replacementPath, livePath); err != nil {
+		t.Fatalf("rename replacement over live path: %v", err)
+	}
+
+	if err := ReopenDatabase(); err != nil {
+		t.Fatalf("ReopenDatabase: %v", err)
+	}
+
+	if _, err := GetTrackByPersistentID("old-1"); err != ErrNoTracksFound {
+		t.Fatalf("old-1 should be gone after reopen, got err=%v", err)
+	}
+	track, err := GetTrackByPersistentID("new-1")
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID(new-1): %v", err)
+	}
+	if track.Name != "New Song" {
+		t.Fatalf("got track %+v, want New Song", track)
+	}
+}
@@ -0,0 +1,59 @@
+package itunes
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRefreshLibraryCacheReturnsCountsMatchingWrittenFile(t *testing.T) {
+	withTestDB(t)
+
+	cacheDir := t.TempDir()
+	t.Setenv("ITUNES_CACHE_DIR", cacheDir)
+
+	payload, err := json.Marshal([]scriptTrack{
+		{ID: "1", Name: "Song One", Artist: "Artist", Collection: "Library"},
+		{ID: "2", Name: "Song Two", Artist: "Artist", Collection: "Library"},
+	})
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	withFakeRunner(t, fakeRunner{stdout: string(payload)})
+
+	result, err := RefreshLibraryCache(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshLibraryCache: %v", err)
+	}
+	if result.TrackCount != 2 {
+		t.Fatalf("TrackCount = %d, want 2", result.TrackCount)
+	}
+	if result.CachePath == "" {
+		t.Fatal("expected a non-empty CachePath")
+	}
+
+	written, err := os.ReadFile(result.CachePath)
+	if err != nil {
+		t.Fatalf("read cache file: %v", err)
+	}
+	var tracks []scriptTrack
+	if err := json.Unmarshal(written, &tracks); err != nil {
+		t.Fatalf("parse cache file: %v", err)
+	}
+	if len(tracks) != result.TrackCount {
+		t.Fatalf("cache file has %d tracks, stats say %d", len(tracks), result.TrackCount)
+	}
+	if filepath.Dir(result.CachePath) != cacheDir {
+		t.Fatalf("CachePath dir = %q, want %q", filepath.Dir(result.CachePath), cacheDir)
+	}
+
+	stats, err := DBManager.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TrackCount != 2 {
+		t.Fatalf("DB TrackCount = %d, want 2", stats.TrackCount)
+	}
+}
@@ -0,0 +1,113 @@
+package itunes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// libraryPlaylistName is the playlist iTunes_Search2_fzf.js scans by
+// default (its 'Library' search query maps to Apple Music's own Library
+// source playlist, literally named "Library"), so it contains every
+// downloaded track and is the right fallback playlist for playing a
+// resolved track that isn't tied to a more specific playlist.
+const libraryPlaylistName = "Library"
+
+// PlayTargetKind classifies what ResolvePlayTarget decided a free-text
+// query refers to.
+type PlayTargetKind string
+
+const (
+	PlayTargetPlaylist PlayTargetKind = "playlist"
+	PlayTargetTrack    PlayTargetKind = "track"
+)
+
+// PlayTarget is ResolvePlayTarget's decision: what kind of thing query
+// matched, enough detail to act on it, and a human-readable reason so
+// callers can show their work instead of silently picking a best guess.
+type PlayTarget struct {
+	Kind     PlayTargetKind `json:"kind"`
+	Playlist string         `json:"playlist,omitempty"`
+	Track    *Track         `json:"track,omitempty"`
+	Reason   string         `json:"reason"`
+}
+
+// ResolvePlayTarget interprets query as free text that could name a
+// playlist, or an artist/album/track to search for, and picks the single
+// best play action. A playlist name match always wins over a track search,
+// since "play <playlist>" unambiguously means "play that whole playlist",
+// while any track can also be reached by searching for it by name.
+func ResolvePlayTarget(query string) (*PlayTarget, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, NewITunesError(ErrNotFound, "resolve play target", fmt.Errorf("empty query"))
+	}
+
+	if playlist, err := findPlaylistByName(query); err != nil {
+		return nil, err
+	} else if playlist != nil {
+		return &PlayTarget{
+			Kind:     PlayTargetPlaylist,
+			Playlist: playlist.Name,
+			Reason:   fmt.Sprintf("%q matches playlist %q", query, playlist.Name),
+		}, nil
+	}
+
+	tracks, err := SearchTracksFromDatabase(query, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 {
+		return nil, ErrNoTracksFound
+	}
+	best := tracks[0]
+	return &PlayTarget{
+		Kind:   PlayTargetTrack,
+		Track:  &best,
+		Reason: fmt.Sprintf("%q best matches track %q by %s", query, best.Name, best.Artist),
+	}, nil
+}
+
+// findPlaylistByName looks for a user playlist whose name
+// case-insensitively equals or contains query, preferring an exact match.
+// Returns nil, nil when nothing matches.
+func findPlaylistByName(query string) (*database.Playlist, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "resolve play target", fmt.Errorf("database not initialized"))
+	}
+	page, err := db.ListPlaylists(nil, true, 0, 0)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "resolve play target", err)
+	}
+
+	lower := strings.ToLower(query)
+	var contains *database.Playlist
+	for i := range page.Playlists {
+		p := page.Playlists[i]
+		name := strings.ToLower(p.Name)
+		if name == lower {
+			return &p, nil
+		}
+		if contains == nil && strings.Contains(name, lower) {
+			contains = &p
+		}
+	}
+	return contains, nil
+}
+
+// PlayResolved drives ResolvePlayTarget's decision through the
+// corresponding playback helper: a playlist plays as a whole, a track
+// plays by name from libraryPlaylistName.
+func PlayResolved(ctx context.Context, target *PlayTarget) (*PlayResult, error) {
+	switch target.Kind {
+	case PlayTargetPlaylist:
+		return PlayPlaylistWithStatus(ctx, target.Playlist)
+	case PlayTargetTrack:
+		return PlayPlaylistTrackWithStatus(ctx, libraryPlaylistName, target.Track.Name)
+	default:
+		return nil, NewITunesError(ErrNotFound, "play resolved target", fmt.Errorf("unknown play target kind %q", target.Kind))
+	}
+}
@@ -0,0 +1,61 @@
+package itunes
+
+import (
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func seedResolveLibrary(t *testing.T) {
+	t.Helper()
+	db := withTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{
+			{PersistentID: "p1", Name: "Windowlicker", Artist: "Aphex Twin", Album: "Windowlicker"},
+			{PersistentID: "p2", Name: "Other Song", Artist: "Someone Else", Album: "Other Album"},
+		},
+		Playlists: []database.RefreshPlaylist{
+			{PersistentID: "pl1", Name: "Chill Vibes", TrackIDs: []string{"p1"}},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+}
+
+func TestResolvePlayTargetMatchesPlaylistByName(t *testing.T) {
+	seedResolveLibrary(t)
+
+	target, err := ResolvePlayTarget("chill vibes")
+	if err != nil {
+		t.Fatalf("ResolvePlayTarget: %v", err)
+	}
+	if target.Kind != PlayTargetPlaylist || target.Playlist != "Chill Vibes" {
+		t.Fatalf("got %+v, want playlist match on Chill Vibes", target)
+	}
+}
+
+func TestResolvePlayTargetFallsBackToTrackSearch(t *testing.T) {
+	seedResolveLibrary(t)
+
+	target, err := ResolvePlayTarget("Windowlicker")
+	if err != nil {
+		t.Fatalf("ResolvePlayTarget: %v", err)
+	}
+	if target.Kind != PlayTargetTrack || target.Track == nil || target.Track.Name != "Windowlicker" {
+		t.Fatalf("got %+v, want track match on Windowlicker", target)
+	}
+}
+
+func TestResolvePlayTargetNoMatchReturnsErrNoTracksFound(t *testing.T) {
+	seedResolveLibrary(t)
+
+	if _, err := ResolvePlayTarget("nothing matches this query"); err != ErrNoTracksFound {
+		t.Fatalf("got %v, want ErrNoTracksFound", err)
+	}
+}
+
+func TestResolvePlayTargetRejectsEmptyQuery(t *testing.T) {
+	if _, err := ResolvePlayTarget("   "); err == nil {
+		t.Fatal("expected an error for an empty query")
+	}
+}
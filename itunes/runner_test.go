@@ -0,0 +1,109 @@
+package itunes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// fakeRunner is a scriptRunner returning canned output, letting playback
+// logic be exercised without osascript or Apple Music present.
+type fakeRunner struct {
+	stdout, stderr string
+	err            error
+}
+
+func (f fakeRunner) Run(ctx context.Context, scriptPath string, args ...string) (string, string, error) {
+	return f.stdout, f.stderr, f.err
+}
+
+func withFakeRunner(t *testing.T, r scriptRunner) {
+	t.Helper()
+	prev := runner
+	runner = r
+	t.Cleanup(func() { runner = prev })
+}
+
+func TestGetNowPlayingLocalTrack(t *testing.T) {
+	withFakeRunner(t, fakeRunner{stdout: `OK:{"state":"playing","artist":"Artist","title":"Song","stream":false}`})
+
+	status, err := GetNowPlaying(context.Background())
+	if err != nil {
+		t.Fatalf("GetNowPlaying: %v", err)
+	}
+	if status.IsStreaming {
+		t.Fatal("expected IsStreaming=false for a local track")
+	}
+	if status.State != StatePlaying || status.Title != "Song" {
+		t.Fatalf("got %+v", status)
+	}
+}
+
+func TestGetNowPlayingStreamingTrack(t *testing.T) {
+	withFakeRunner(t, fakeRunner{stdout: `OK:{"state":"playing","artist":"Radio Host","title":"Live Show","stream":true}`})
+
+	status, err := GetNowPlaying(context.Background())
+	if err != nil {
+		t.Fatalf("GetNowPlaying: %v", err)
+	}
+	if !status.IsStreaming {
+		t.Fatal("expected IsStreaming=true for a stream")
+	}
+}
+
+func TestGetNowPlayingErrorOutput(t *testing.T) {
+	withFakeRunner(t, fakeRunner{stdout: "ERROR:Music is not running"})
+
+	if _, err := GetNowPlaying(context.Background()); err == nil {
+		t.Fatal("expected an error for an ERROR: payload")
+	}
+}
+
+func TestGetNowPlayingMalformedJSONFallsThroughAsError(t *testing.T) {
+	withFakeRunner(t, fakeRunner{stdout: "OK:{not json"})
+
+	if _, err := GetNowPlaying(context.Background()); err == nil {
+		t.Fatal("expected an error for malformed JSON")
+	}
+}
+
+// TestGetNowPlayingPersistentIDResolvesToDBTrack confirms the ID returned
+// by GetNowPlayingPersistentID is the same persistent ID GetTrackByPersistentID
+// expects, not some other identifier (e.g. a database row id) that happens
+// to look similar.
+func TestGetNowPlayingPersistentIDResolvesToDBTrack(t *testing.T) {
+	db := withTestDB(t)
+	if _, err := db.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{
+			{PersistentID: "ABCD1234", Name: "Current Song", Artist: "Aphex Twin"},
+		},
+	}); err != nil {
+		t.Fatalf("PopulateFromRefreshScript: %v", err)
+	}
+	withFakeRunner(t, fakeRunner{stdout: `OK:{"persistent_id":"ABCD1234"}`})
+
+	id, err := GetNowPlayingPersistentID(context.Background())
+	if err != nil {
+		t.Fatalf("GetNowPlayingPersistentID: %v", err)
+	}
+	if id != "ABCD1234" {
+		t.Fatalf("got %q, want %q", id, "ABCD1234")
+	}
+
+	track, err := GetTrackByPersistentID(id)
+	if err != nil {
+		t.Fatalf("GetTrackByPersistentID(%q): %v", id, err)
+	}
+	if track.Name != "Current Song" {
+		t.Fatalf("got track %+v, want Current Song", track)
+	}
+}
+
+func TestGetNowPlayingPersistentIDErrorOutput(t *testing.T) {
+	withFakeRunner(t, fakeRunner{stdout: "ERROR:no track is currently playing"})
+
+	if _, err := GetNowPlayingPersistentID(context.Background()); err == nil {
+		t.Fatal("expected an error for an ERROR: payload")
+	}
+}
@@ -0,0 +1,24 @@
+package itunes
+
+import "testing"
+
+func TestSearchAlbumsAndSearchArtists(t *testing.T) {
+	withTestDB(t)
+	seedMilesDavisTracks(t)
+
+	artists, err := SearchArtists("Miles Davis", nil)
+	if err != nil {
+		t.Fatalf("SearchArtists: %v", err)
+	}
+	if len(artists) != 1 || artists[0].Artist != "Miles Davis" || artists[0].TrackCount != 3 {
+		t.Fatalf("got %+v, want a single Miles Davis entry with 3 tracks", artists)
+	}
+
+	albums, err := SearchAlbums("Miles Davis", nil)
+	if err != nil {
+		t.Fatalf("SearchAlbums: %v", err)
+	}
+	if len(albums) != 2 {
+		t.Fatalf("got %d albums, want 2 (Kind of Blue, Someday My Prince Will Come)", len(albums))
+	}
+}
@@ -0,0 +1,54 @@
+package itunes
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+)
+
+// settlePollInterval is how often pollUntilSettled re-checks now-playing
+// status while waiting for Apple Music to catch up after a play command.
+var settlePollInterval = durationFromEnvMillis("ITUNES_SETTLE_POLL_INTERVAL_MS", 150*time.Millisecond)
+
+// settleDelay is the maximum time pollUntilSettled waits for a playing
+// state before giving up and returning whatever now-playing last reported.
+// Configurable via ITUNES_SETTLE_DELAY_MS since a fixed sleep is either too
+// slow on a fast machine or too short on a loaded one.
+var settleDelay = durationFromEnvMillis("ITUNES_SETTLE_DELAY_MS", time.Second)
+
+// streamSettleDelay is settleDelay's counterpart for PlayStreamURL, which
+// needs longer to buffer before now-playing reports anything useful.
+var streamSettleDelay = durationFromEnvMillis("ITUNES_STREAM_SETTLE_DELAY_MS", 3*time.Second)
+
+func durationFromEnvMillis(envVar string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(envVar); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	return fallback
+}
+
+// pollUntilSettled polls getStatus every interval, up to maxWait, until it
+// reports a playing or paused state, returning as soon as one does instead
+// of always waiting the full duration. If maxWait elapses first, it
+// returns the last status/error observed (which may still be useful, e.g.
+// a stopped state genuinely means playback didn't start).
+func pollUntilSettled(ctx context.Context, getStatus func(context.Context) (*NowPlayingStatus, error), interval, maxWait time.Duration) (*NowPlayingStatus, error) {
+	deadline := time.Now().Add(maxWait)
+	for {
+		status, err := getStatus(ctx)
+		if err == nil && (status.State == StatePlaying || status.State == StatePaused) {
+			return status, nil
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			return status, err
+		}
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package itunes
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPollUntilSettledReturnsAsSoonAsPlaying(t *testing.T) {
+	calls := 0
+	getStatus := func(ctx context.Context) (*NowPlayingStatus, error) {
+		calls++
+		if calls < 3 {
+			return &NowPlayingStatus{State: StateStopped}, nil
+		}
+		return &NowPlayingStatus{State: StatePlaying, Title: "Song"}, nil
+	}
+
+	start := time.Now()
+	status, err := pollUntilSettled(context.Background(), getStatus, time.Millisecond, time.Second)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("pollUntilSettled: %v", err)
+	}
+	if status.Title != "Song" {
+		t.Fatalf("got %+v, want the playing status", status)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3 (stopped returned a generous margin below maxWait)", calls)
+	}
+	if elapsed >= time.Second {
+		t.Fatalf("pollUntilSettled took %s, expected to return well before maxWait", elapsed)
+	}
+}
+
+func TestPollUntilSettledGivesUpAtMaxWait(t *testing.T) {
+	getStatus := func(ctx context.Context) (*NowPlayingStatus, error) {
+		return &NowPlayingStatus{State: StateStopped}, nil
+	}
+
+	status, err := pollUntilSettled(context.Background(), getStatus, time.Millisecond, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("pollUntilSettled: %v", err)
+	}
+	if status.State != StateStopped {
+		t.Fatalf("got %+v, want the last observed (stopped) status", status)
+	}
+}
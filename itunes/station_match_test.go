@@ -0,0 +1,48 @@
+package itunes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+func TestEnrichStreamNowPlayingLinksKnownStation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	db := withTestDB(t)
+	station := &database.RadioStation{Name: "Deep Space One", URL: srv.URL + "?app=music", Genre: "Ambient", Homepage: "https://example.com"}
+	if err := db.AddRadioStation(station); err != nil {
+		t.Fatalf("AddRadioStation: %v", err)
+	}
+
+	status := &NowPlayingStatus{State: StatePlaying, IsStreaming: true}
+	enrichStreamNowPlaying(context.Background(), status, srv.URL)
+
+	if status.Station == nil {
+		t.Fatal("expected Station to be populated from the cached radio_stations row")
+	}
+	if status.Station.Name != "Deep Space One" || status.Station.Genre != "Ambient" {
+		t.Fatalf("got %+v, want name=Deep Space One genre=Ambient", status.Station)
+	}
+}
+
+func TestEnrichStreamNowPlayingUnknownStation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	withTestDB(t)
+	status := &NowPlayingStatus{State: StatePlaying, IsStreaming: true}
+	enrichStreamNowPlaying(context.Background(), status, srv.URL)
+
+	if status.Station != nil {
+		t.Fatalf("expected no Station match, got %+v", status.Station)
+	}
+}
@@ -0,0 +1,127 @@
+package itunes
+
+import (
+	"fmt"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// GetLibraryStats returns row counts across the main tables, for the stats
+// MCP resource and diagnostics.
+func GetLibraryStats() (*database.Stats, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get library stats", fmt.Errorf("database not initialized"))
+	}
+	return db.GetStats()
+}
+
+// RatingStats is GetRatingStats's return shape: a track count per rating
+// bucket (0, 20, 40, 60, 80, 100) plus the starred/unstarred split.
+type RatingStats struct {
+	ByRating       map[int]int `json:"by_rating"`
+	StarredCount   int         `json:"starred_count"`
+	UnstarredCount int         `json:"unstarred_count"`
+}
+
+// GetRatingStats returns the rating distribution and starred/unstarred
+// breakdown, for the itunes rating-stats command and the stats MCP
+// resource.
+func GetRatingStats() (*RatingStats, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get rating stats", fmt.Errorf("database not initialized"))
+	}
+	dist, err := db.GetRatingDistribution()
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "get rating stats", err)
+	}
+	starred, unstarred, err := db.GetStarredBreakdown()
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "get rating stats", err)
+	}
+	return &RatingStats{ByRating: dist, StarredCount: starred, UnstarredCount: unstarred}, nil
+}
+
+// DefaultTopLimit caps GetTopTracks/GetTopArtists when callers don't pass
+// an explicit limit.
+const DefaultTopLimit = 20
+
+// TopStats is GetTopPlayed's return shape: the most-played tracks and the
+// artists with the highest summed play count.
+type TopStats struct {
+	Tracks  []database.Track           `json:"tracks"`
+	Artists []database.ArtistPlayCount `json:"artists"`
+}
+
+// GetTopPlayed returns the limit most-played tracks and artists. limit <= 0
+// uses DefaultTopLimit.
+func GetTopPlayed(limit int) (*TopStats, error) {
+	if limit <= 0 {
+		limit = DefaultTopLimit
+	}
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get top played", fmt.Errorf("database not initialized"))
+	}
+	tracks, err := db.GetTopTracks(limit)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "get top played", err)
+	}
+	artists, err := db.GetTopArtists(limit)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "get top played", err)
+	}
+	return &TopStats{Tracks: tracks, Artists: artists}, nil
+}
+
+// GetRecentlyAdded returns the limit most recently added tracks, newest
+// first. limit <= 0 uses DefaultTopLimit.
+func GetRecentlyAdded(limit int) ([]database.Track, error) {
+	if limit <= 0 {
+		limit = DefaultTopLimit
+	}
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get recently added", fmt.Errorf("database not initialized"))
+	}
+	tracks, err := db.GetRecentlyAdded(limit)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "get recently added", err)
+	}
+	return tracks, nil
+}
+
+// GetPopularSearches returns the limit most frequently logged search
+// queries, most popular first. limit <= 0 uses DefaultTopLimit. Returns an
+// empty slice unless ITUNES_SEARCH_LOG has been enabled at some point, since
+// search logging is opt-in.
+func GetPopularSearches(limit int) ([]database.PopularSearch, error) {
+	if limit <= 0 {
+		limit = DefaultTopLimit
+	}
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get popular searches", fmt.Errorf("database not initialized"))
+	}
+	popular, err := db.GetPopularSearches(limit)
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "get popular searches", err)
+	}
+	return popular, nil
+}
+
+// GetTracksAddedSinceLastRefresh returns the tracks added by the most
+// recent RefreshLibraryCache run, for a "what's new since last refresh"
+// changelog.
+func GetTracksAddedSinceLastRefresh() ([]database.Track, error) {
+	db, _ := dbHandles()
+	if db == nil {
+		return nil, NewITunesError(ErrDatabase, "get tracks added since last refresh", fmt.Errorf("database not initialized"))
+	}
+	tracks, err := db.GetTracksAddedSinceLastRefresh()
+	if err != nil {
+		return nil, NewITunesError(ErrDatabase, "get tracks added since last refresh", err)
+	}
+	return tracks, nil
+}
@@ -0,0 +1,125 @@
+package itunes
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// streamMetadataTimeout bounds how long GetStreamMetadata will wait for the
+// station to respond and emit one ICY metadata block, so a slow or
+// non-responding stream can't hang PlayStreamURL.
+const streamMetadataTimeout = 5 * time.Second
+
+// StreamMetadata is what a Shoutcast/Icecast station's ICY metadata block
+// reveals about what's currently playing.
+type StreamMetadata struct {
+	StationName string `json:"station_name,omitempty"`
+	SongTitle   string `json:"song_title,omitempty"`
+}
+
+var streamTitlePattern = regexp.MustCompile(`StreamTitle='([^']*)';`)
+
+// GetStreamMetadata connects to an HTTP(S) internet radio stream and reads
+// a single ICY metadata block, without buffering or playing any audio. It
+// requests metadata via the "Icy-MetaData: 1" header, reads the
+// station-supplied "icy-name" header, then skips exactly "icy-metaint"
+// bytes of audio before reading the one metadata block that follows, per
+// the Shoutcast/Icecast ICY protocol. Streams that don't support ICY
+// metadata (no "icy-metaint" header) return a StreamMetadata with only
+// StationName set, and no error.
+func GetStreamMetadata(ctx context.Context, streamURL string) (*StreamMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, streamMetadataTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		return nil, NewITunesError(ErrJXAScript, "build stream metadata request", err)
+	}
+	req.Header.Set("Icy-MetaData", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, NewITunesError(ErrTimeout, "connect to stream", err)
+	}
+	defer resp.Body.Close()
+
+	meta := &StreamMetadata{StationName: resp.Header.Get("icy-name")}
+
+	metaint, err := strconv.Atoi(resp.Header.Get("icy-metaint"))
+	if err != nil || metaint <= 0 {
+		return meta, nil
+	}
+
+	r := bufio.NewReader(resp.Body)
+	if _, err := r.Discard(metaint); err != nil {
+		return meta, nil
+	}
+
+	lengthByte, err := r.ReadByte()
+	if err != nil {
+		return meta, nil
+	}
+	blockLen := int(lengthByte) * 16
+	if blockLen == 0 {
+		return meta, nil
+	}
+
+	block := make([]byte, blockLen)
+	if _, err := readFull(r, block); err != nil {
+		return meta, nil
+	}
+
+	if m := streamTitlePattern.FindSubmatch(block); m != nil {
+		meta.SongTitle = strings.TrimSpace(string(m[1]))
+	}
+	return meta, nil
+}
+
+// readFull is io.ReadFull without importing io solely for this one call
+// site.
+func readFull(r *bufio.Reader, buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		nn, err := r.Read(buf[n:])
+		n += nn
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// enrichStreamNowPlaying overwrites status.Title with the station's
+// currently-announced song title, when GetStreamMetadata can determine one,
+// and attaches status.Station when streamURL resolves to a cached station.
+// Failures are swallowed: both enhancements are best-effort, and
+// PlayStreamURL should still report success when a station simply doesn't
+// announce ICY metadata or isn't in the radio_stations cache.
+func enrichStreamNowPlaying(ctx context.Context, status *NowPlayingStatus, streamURL string) {
+	if status == nil || !status.IsStreaming {
+		return
+	}
+
+	if meta, err := GetStreamMetadata(ctx, streamURL); err == nil && meta != nil {
+		if meta.SongTitle != "" {
+			status.Title = meta.SongTitle
+		}
+		if status.Album == "" && meta.StationName != "" {
+			status.Album = meta.StationName
+		}
+	}
+
+	if station, err := FindStationByURL(streamURL); err == nil {
+		status.Station = &StationInfo{
+			ID:       station.ID,
+			Name:     station.Name,
+			Genre:    station.Genre,
+			Homepage: station.Homepage,
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package itunes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetStreamMetadataParsesICYBlock(t *testing.T) {
+	const metaint = 16
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Icy-MetaData") != "1" {
+			t.Errorf("expected Icy-MetaData: 1 header, got %q", r.Header.Get("Icy-MetaData"))
+		}
+		w.Header().Set("icy-name", "Test Radio")
+		w.Header().Set("icy-metaint", "16")
+		w.WriteHeader(http.StatusOK)
+
+		w.Write(make([]byte, metaint))
+
+		payload := []byte("StreamTitle='Artist - Song';")
+		blocks := (len(payload) + 15) / 16
+		padded := make([]byte, blocks*16)
+		copy(padded, payload)
+		w.Write([]byte{byte(blocks)})
+		w.Write(padded)
+	}))
+	defer srv.Close()
+
+	meta, err := GetStreamMetadata(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("GetStreamMetadata: %v", err)
+	}
+	if meta.StationName != "Test Radio" {
+		t.Errorf("StationName = %q, want %q", meta.StationName, "Test Radio")
+	}
+	if meta.SongTitle != "Artist - Song" {
+		t.Errorf("SongTitle = %q, want %q", meta.SongTitle, "Artist - Song")
+	}
+}
+
+func TestGetStreamMetadataNoICYSupport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("plain audio bytes"))
+	}))
+	defer srv.Close()
+
+	meta, err := GetStreamMetadata(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("GetStreamMetadata: %v", err)
+	}
+	if meta.SongTitle != "" {
+		t.Errorf("SongTitle = %q, want empty for a non-ICY stream", meta.SongTitle)
+	}
+}
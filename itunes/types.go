@@ -0,0 +1,106 @@
+package itunes
+
+import (
+	"time"
+
+	"github.com/chew-z/itunes.vim/database"
+)
+
+// Track is the API-facing track shape returned by search, playlist, and
+// lookup calls. See database.Track for the full cached row.
+type Track struct {
+	PersistentID string     `json:"persistent_id"`
+	Name         string     `json:"name"`
+	Artist       string     `json:"artist"`
+	Album        string     `json:"album"`
+	Genre        string     `json:"genre"`
+	Duration     float64    `json:"duration,omitempty"`
+	Rating       int        `json:"rating"`
+	Starred      bool       `json:"starred"`
+	Disliked     bool       `json:"disliked,omitempty"`
+	PlayCount    int        `json:"play_count,omitempty"`
+	LastPlayed   *time.Time `json:"last_played,omitempty"`
+	Ranking      float64    `json:"ranking"`
+	Relevance    float64    `json:"relevance,omitempty"`
+	TrackKind    string     `json:"track_kind,omitempty"`
+	IsStreaming  bool       `json:"is_streaming,omitempty"`
+	StreamURL    string     `json:"stream_url,omitempty"`
+}
+
+func trackFromDB(t database.Track) Track {
+	return Track{
+		PersistentID: t.PersistentID,
+		Name:         t.Name,
+		Artist:       t.Artist,
+		Album:        t.Album,
+		Genre:        t.Genre,
+		Duration:     t.Duration,
+		Rating:       t.Rating,
+		Starred:      t.Starred,
+		Disliked:     t.Disliked,
+		PlayCount:    t.PlayCount,
+		LastPlayed:   t.LastPlayed,
+		Ranking:      t.Ranking,
+		Relevance:    t.Relevance,
+		TrackKind:    t.TrackKind,
+		IsStreaming:  t.IsStreaming,
+		StreamURL:    t.StreamURL,
+	}
+}
+
+// PlaybackState enumerates the states reported by GetNowPlaying.
+type PlaybackState string
+
+const (
+	StatePlaying PlaybackState = "playing"
+	StatePaused  PlaybackState = "paused"
+	StateStopped PlaybackState = "stopped"
+)
+
+// NowPlayingStatus is the result of GetNowPlaying.
+type NowPlayingStatus struct {
+	State       PlaybackState `json:"state"`
+	Artist      string        `json:"artist"`
+	Title       string        `json:"title"`
+	Album       string        `json:"album"`
+	Position    float64       `json:"position"`
+	Duration    float64       `json:"duration"`
+	IsStreaming bool          `json:"is_streaming"`
+	Station     *StationInfo  `json:"station,omitempty"`
+}
+
+// StationInfo is the subset of a cached radio_stations row worth attaching
+// to a NowPlayingStatus when a playing stream's URL resolves to a known
+// station.
+type StationInfo struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Genre    string `json:"genre,omitempty"`
+	Homepage string `json:"homepage,omitempty"`
+}
+
+// PlayResult is returned by the playback helpers after driving Apple Music
+// and re-reading now-playing status.
+type PlayResult struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message,omitempty"`
+	Status  *NowPlayingStatus `json:"status,omitempty"`
+	Matched bool              `json:"matched,omitempty"`
+}
+
+// RefreshStats summarizes a RefreshLibraryCache run.
+type RefreshStats struct {
+	TrackCount     int           `json:"track_count"`
+	PlaylistCount  int           `json:"playlist_count"`
+	SkippedTracks  int           `json:"skipped_tracks"`
+	ProcessingTime time.Duration `json:"processing_time"`
+}
+
+// RefreshResult is RefreshLibraryCache's full return value: the stats plus
+// where the raw script output was cached, so callers like the
+// refresh_library MCP handler and itunes-migrate don't need to separately
+// re-read and re-parse the cache file to report on a refresh.
+type RefreshResult struct {
+	RefreshStats
+	CachePath string `json:"cache_path,omitempty"`
+}
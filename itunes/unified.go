@@ -0,0 +1,41 @@
+package itunes
+
+import "github.com/chew-z/itunes.vim/database"
+
+// unifiedStationLimit caps how many stations UnifiedSearch includes, kept
+// small since it's a secondary section alongside track results.
+const unifiedStationLimit = 10
+
+// UnifiedResult combines track and station search results for a single
+// query, so clients don't need to make two separate calls.
+type UnifiedResult struct {
+	Query            string                  `json:"query"`
+	Tracks           []Track                 `json:"tracks"`
+	Stations         []database.RadioStation `json:"stations"`
+	TracksSearched   bool                    `json:"tracks_searched"`
+	StationsSearched bool                    `json:"stations_searched"`
+}
+
+// UnifiedSearch runs both a track search and a station search for query,
+// returning whichever sections succeeded. A failure in one section doesn't
+// block the other from reporting results.
+func UnifiedSearch(query string) (*UnifiedResult, error) {
+	result := &UnifiedResult{Query: query}
+
+	if tracks, err := SearchTracksFromDatabase(query, nil); err == nil {
+		result.Tracks = tracks
+		result.TracksSearched = true
+	} else if err == ErrLibraryEmpty {
+		result.TracksSearched = true
+	}
+
+	if stations, err := SearchStations(query, &database.RadioStationFilters{ActiveOnly: true}, unifiedStationLimit); err == nil {
+		result.Stations = stations
+		result.StationsSearched = true
+	}
+
+	if !result.TracksSearched && !result.StationsSearched {
+		return nil, NewITunesError(ErrDatabase, "unified search", nil)
+	}
+	return result, nil
+}
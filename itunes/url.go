@@ -0,0 +1,51 @@
+package itunes
+
+import (
+	"net/url"
+	"strings"
+)
+
+// NormalizeStreamURL validates a URL intended for PlayStreamURL and
+// converts Apple Music share links (https://music.apple.com/...) into the
+// itmss:// form Apple Music expects, so callers don't need to special-case
+// the conversion themselves. Unsupported schemes are rejected before a
+// caller would otherwise spawn osascript with them.
+func NormalizeStreamURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", NewITunesError(ErrJXAScript, "normalize stream url", errEmptyURL)
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", NewITunesError(ErrJXAScript, "normalize stream url", err)
+	}
+
+	switch strings.ToLower(parsed.Scheme) {
+	case "itmss":
+		return raw, nil
+	case "http", "https":
+		if strings.EqualFold(parsed.Host, "music.apple.com") {
+			converted := *parsed
+			converted.Scheme = "itmss"
+			q := converted.Query()
+			q.Set("app", "music")
+			converted.RawQuery = q.Encode()
+			return converted.String(), nil
+		}
+		// Generic http(s) stream URLs (internet radio) are passed through.
+		return raw, nil
+	default:
+		return "", NewITunesError(ErrJXAScript, "normalize stream url", errUnsupportedScheme(parsed.Scheme))
+	}
+}
+
+var errEmptyURL = urlError("url is empty")
+
+type urlError string
+
+func (e urlError) Error() string { return string(e) }
+
+func errUnsupportedScheme(scheme string) error {
+	return urlError("unsupported URL scheme: " + scheme)
+}
@@ -0,0 +1,56 @@
+package itunes
+
+import "testing"
+
+func TestNormalizeStreamURL(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "apple music share link converts to itmss",
+			in:   "https://music.apple.com/us/album/kind-of-blue/1065981054",
+			want: "itmss://music.apple.com/us/album/kind-of-blue/1065981054?app=music",
+		},
+		{
+			name: "plain internet radio stream passes through",
+			in:   "https://icecast.example.com/stream",
+			want: "https://icecast.example.com/stream",
+		},
+		{
+			name: "itmss passes through unchanged",
+			in:   "itmss://music.apple.com/us/album/1?app=music",
+			want: "itmss://music.apple.com/us/album/1?app=music",
+		},
+		{
+			name:    "unsupported scheme rejected",
+			in:      "ftp://example.com/file.mp3",
+			wantErr: true,
+		},
+		{
+			name:    "empty url rejected",
+			in:      "",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := NormalizeStreamURL(c.in)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Fatalf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
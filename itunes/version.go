@@ -0,0 +1,105 @@
+package itunes
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type versionResponse struct {
+	Version string `json:"version"`
+}
+
+// versionMu guards the cached Music app version, following the same
+// read/write-lock convention dbMu uses for DBManager/SearchManager.
+var (
+	versionMu    sync.RWMutex
+	versionCache string
+)
+
+// GetMusicAppVersion returns the installed Music app's version string (e.g.
+// "1.3.5.4"), caching it after the first successful lookup since it can't
+// change while the process is running. A failed lookup is not cached, so a
+// transient JXA failure doesn't wedge every later feature-gating check.
+func GetMusicAppVersion(ctx context.Context) (string, error) {
+	versionMu.RLock()
+	cached := versionCache
+	versionMu.RUnlock()
+	if cached != "" {
+		return cached, nil
+	}
+
+	resp, err := runScriptJSON[versionResponse](ctx, filepath.Join(scriptDir(), "iTunes_Get_Version.js"))
+	if err != nil {
+		return "", err
+	}
+
+	versionMu.Lock()
+	versionCache = resp.Version
+	versionMu.Unlock()
+	return resp.Version, nil
+}
+
+// resetVersionCache clears the cached Music app version, so tests can
+// simulate a fresh process observing a different installed version.
+func resetVersionCache() {
+	versionMu.Lock()
+	versionCache = ""
+	versionMu.Unlock()
+}
+
+// featureMinVersion maps a scriptable feature to the earliest Music app
+// version known to support it. Versions below this (or a version that fails
+// to parse) are treated as not supporting the feature.
+var featureMinVersion = map[string]string{
+	"crossfade": "1.0.0",
+	"eq_preset": "1.0.0",
+	"lyrics":    "1.1.0",
+}
+
+// requireFeature returns ErrUnsupportedFeature if the installed Music app's
+// version is older than feature's minimum, so callers can report that
+// distinctly from a JXA script simply failing. An unrecognized feature name
+// is a programmer error and always passes, since it isn't gated.
+func requireFeature(ctx context.Context, feature string) error {
+	minVersion, gated := featureMinVersion[feature]
+	if !gated {
+		return nil
+	}
+	version, err := GetMusicAppVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if compareVersions(version, minVersion) < 0 {
+		return NewITunesError(ErrUnsupportedFeature, fmt.Sprintf("%s requires Music %s or later, installed version is %s", feature, minVersion, version), nil)
+	}
+	return nil
+}
+
+// compareVersions compares two dotted numeric version strings component by
+// component (e.g. "1.3.5.4" vs "1.3.10"), returning -1, 0, or 1 the way
+// strings.Compare does. A non-numeric component sorts as 0, so a version
+// string JXA can't parse cleanly degrades to "equal" rather than erroring.
+func compareVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var an, bn int
+		if i < len(as) {
+			an, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			bn, _ = strconv.Atoi(bs[i])
+		}
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package itunes
+
+import (
+	"context"
+	"testing"
+)
+
+func withMusicAppVersion(t *testing.T, version string) {
+	t.Helper()
+	withFakeRunner(t, fakeRunner{stdout: `OK:{"version":"` + version + `"}`})
+	resetVersionCache()
+	t.Cleanup(resetVersionCache)
+}
+
+func TestGetMusicAppVersionCachesAfterFirstLookup(t *testing.T) {
+	withMusicAppVersion(t, "1.3.5")
+
+	got, err := GetMusicAppVersion(context.Background())
+	if err != nil {
+		t.Fatalf("GetMusicAppVersion: %v", err)
+	}
+	if got != "1.3.5" {
+		t.Fatalf("got %q, want 1.3.5", got)
+	}
+
+	withFakeRunner(t, fakeRunner{err: context.DeadlineExceeded})
+	got, err = GetMusicAppVersion(context.Background())
+	if err != nil {
+		t.Fatalf("GetMusicAppVersion (cached): %v", err)
+	}
+	if got != "1.3.5" {
+		t.Fatalf("got %q, want cached 1.3.5", got)
+	}
+}
+
+func TestRequireFeatureGatesOnVersion(t *testing.T) {
+	withMusicAppVersion(t, "0.9.0")
+
+	err := requireFeature(context.Background(), "lyrics")
+	if err == nil {
+		t.Fatal("expected ErrUnsupportedFeature for an older version")
+	}
+	itErr, ok := err.(*ITunesError)
+	if !ok || itErr.Kind != ErrUnsupportedFeature {
+		t.Fatalf("got %v, want an ErrUnsupportedFeature ITunesError", err)
+	}
+}
+
+func TestRequireFeatureAllowsNewerVersion(t *testing.T) {
+	withMusicAppVersion(t, "1.1.0")
+
+	if err := requireFeature(context.Background(), "lyrics"); err != nil {
+		t.Fatalf("requireFeature: %v", err)
+	}
+}
+
+func TestRequireFeatureIgnoresUnknownFeature(t *testing.T) {
+	withMusicAppVersion(t, "0.1.0")
+
+	if err := requireFeature(context.Background(), "not_a_real_feature"); err != nil {
+		t.Fatalf("requireFeature: %v", err)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.3.5", "1.3.5", 0},
+		{"1.3.5", "1.3.10", -1},
+		{"1.10.0", "1.3.0", 1},
+		{"1.3", "1.3.0", 0},
+	}
+	for _, c := range cases {
+		if got := compareVersions(c.a, c.b); got != c.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
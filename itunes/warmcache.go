@@ -0,0 +1,63 @@
+package itunes
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// warmCacheQueriesEnvVar lists queries (comma-separated) to pre-run
+// against the search cache at startup or via the "itunes warm-cache"
+// command, so a fresh server's first real searches for them don't pay a
+// cold scan. Unset disables both.
+const warmCacheQueriesEnvVar = "ITUNES_WARM_CACHE_QUERIES"
+
+// WarmCacheQueriesFromConfig parses warmCacheQueriesEnvVar into a query
+// list, trimming whitespace and dropping empty entries. Returns nil if
+// unset.
+func WarmCacheQueriesFromConfig() []string {
+	v := os.Getenv(warmCacheQueriesEnvVar)
+	if v == "" {
+		return nil
+	}
+	parts := strings.Split(v, ",")
+	queries := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			queries = append(queries, p)
+		}
+	}
+	return queries
+}
+
+// WarmSearchCache runs queries through the search cache. If queries is
+// empty, it falls back to WarmCacheQueriesFromConfig, so the "itunes
+// warm-cache" command works with or without explicit arguments.
+func WarmSearchCache(queries []string) error {
+	_, sm := dbHandles()
+	if sm == nil {
+		return NewITunesError(ErrDatabase, "warm cache", fmt.Errorf("database not initialized"))
+	}
+	if len(queries) == 0 {
+		queries = WarmCacheQueriesFromConfig()
+	}
+	sm.WarmCache(queries)
+	return nil
+}
+
+// MaybeWarmCache pre-runs WarmCacheQueriesFromConfig's queries, if any are
+// configured, so a fresh server's first searches for them are served from
+// cache instead of paying a cold scan. Unlike MaybeAutoRefresh it runs
+// synchronously: warming a handful of queries is fast relative to a
+// library refresh, and callers (e.g. runMCP) want it done before serving.
+func MaybeWarmCache() {
+	queries := WarmCacheQueriesFromConfig()
+	if len(queries) == 0 {
+		return
+	}
+	_, sm := dbHandles()
+	if sm == nil {
+		return
+	}
+	sm.WarmCache(queries)
+}
@@ -0,0 +1,40 @@
+// Package logging provides a thin wrapper around log/slog used by the
+// itunes CLI, the MCP server, and the migrate tool so all three report
+// diagnostics in a consistent shape.
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+var logger *slog.Logger
+
+// InitLogger configures the package-level logger for the given level
+// ("debug", "info", "warn", "error"). Unknown levels fall back to "info".
+func InitLogger(level string) *slog.Logger {
+	var lvl slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		lvl = slog.LevelDebug
+	case "warn", "warning":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		lvl = slog.LevelInfo
+	}
+	logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: lvl}))
+	slog.SetDefault(logger)
+	return logger
+}
+
+// Logger returns the current package-level logger, initializing a default
+// info-level logger if InitLogger hasn't been called yet.
+func Logger() *slog.Logger {
+	if logger == nil {
+		return InitLogger("info")
+	}
+	return logger
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/database"
+	"github.com/chew-z/itunes.vim/itunes"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func withSeededTrack(t *testing.T) {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "library.db")
+	if err := itunes.InitDatabase(dbPath); err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	t.Cleanup(func() { _ = itunes.CloseDatabase() })
+
+	_, err := itunes.DBManager.PopulateFromRefreshScript(&database.RefreshResponse{
+		Tracks: []database.RefreshTrack{
+			{PersistentID: "seeded-1", Name: "Blue in Green", Artist: "Miles Davis", Album: "Kind of Blue", Genre: "Jazz", Rating: 80},
+		},
+	})
+	if err != nil {
+		t.Fatalf("seed track: %v", err)
+	}
+}
+
+func TestGetTrackHandlerReturnsSeededTrack(t *testing.T) {
+	withSeededTrack(t)
+
+	result, err := getTrackHandler(context.Background(), requestWithArgs(map[string]interface{}{"track_id": "seeded-1"}))
+	if err != nil {
+		t.Fatalf("getTrackHandler: %v", err)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("result content is %T, want mcp.TextContent", result.Content[0])
+	}
+	for _, want := range []string{"Blue in Green", "Miles Davis", "Jazz"} {
+		if !strings.Contains(text.Text, want) {
+			t.Errorf("result %q missing %q", text.Text, want)
+		}
+	}
+}
+
+func TestGetTrackHandlerUnknownIDIsFriendly(t *testing.T) {
+	withSeededTrack(t)
+
+	result, err := getTrackHandler(context.Background(), requestWithArgs(map[string]interface{}{"track_id": "does-not-exist"}))
+	if err != nil {
+		t.Fatalf("getTrackHandler: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent)
+	if text.Text != "track not found" {
+		t.Fatalf("got %q, want %q", text.Text, "track not found")
+	}
+}
@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/chew-z/itunes.vim/itunes"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// textResult JSON-marshals v and wraps it as a tool text result.
+func textResult(v interface{}) (*mcp.CallToolResult, error) {
+	data, err := marshalIndent(v)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// errorBody is the JSON shape every MCP tool error result carries, so
+// clients can branch on Code instead of matching Message text.
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// toolResultError renders code and message as a tool error result whose
+// text is a JSON errorBody.
+func toolResultError(code, message string) *mcp.CallToolResult {
+	data, err := json.Marshal(errorBody{Code: code, Message: message})
+	if err != nil {
+		return mcp.NewToolResultError(message)
+	}
+	return mcp.NewToolResultError(string(data))
+}
+
+// errorResult wraps err as a tool error result. When err is (or wraps) an
+// *itunes.ITunesError, its ErrorCode() becomes the result's machine-readable
+// code; otherwise the code is "unknown".
+func errorResult(err error) (*mcp.CallToolResult, error) {
+	code := "unknown"
+	var itErr *itunes.ITunesError
+	if errors.As(err, &itErr) {
+		code = itErr.ErrorCode()
+	}
+	return toolResultError(code, err.Error()), nil
+}
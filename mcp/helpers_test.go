@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/chew-z/itunes.vim/itunes"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func decodeErrorBody(t *testing.T, result *mcp.CallToolResult) errorBody {
+	t.Helper()
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("result content is %T, want mcp.TextContent", result.Content[0])
+	}
+	var body errorBody
+	if err := json.Unmarshal([]byte(text.Text), &body); err != nil {
+		t.Fatalf("unmarshal error body: %v (text: %s)", err, text.Text)
+	}
+	return body
+}
+
+func TestErrorResultUsesITunesErrorCode(t *testing.T) {
+	result, err := errorResult(itunes.NewITunesError(itunes.ErrNotFound, "no tracks found", nil))
+	if err != nil {
+		t.Fatalf("errorResult: %v", err)
+	}
+	body := decodeErrorBody(t, result)
+	if body.Code != "not_found" || body.Message != "no tracks found" {
+		t.Fatalf("got %+v, want code=not_found message=\"no tracks found\"", body)
+	}
+}
+
+func TestErrorResultFallsBackToUnknownCode(t *testing.T) {
+	result, err := errorResult(fmt.Errorf("plain failure"))
+	if err != nil {
+		t.Fatalf("errorResult: %v", err)
+	}
+	body := decodeErrorBody(t, result)
+	if body.Code != "unknown" {
+		t.Fatalf("got code %q, want unknown", body.Code)
+	}
+}
+
+func TestParamErrorResultCodes(t *testing.T) {
+	missing := decodeErrorBody(t, paramErrorResult(missingParam("url")))
+	if missing.Code != "missing_parameter" {
+		t.Fatalf("got code %q, want missing_parameter", missing.Code)
+	}
+
+	invalid := decodeErrorBody(t, paramErrorResult(invalidParam("url", "must be https")))
+	if invalid.Code != "invalid_parameter" {
+		t.Fatalf("got code %q, want invalid_parameter", invalid.Code)
+	}
+}
@@ -0,0 +1,7 @@
+package mcp
+
+import "encoding/json"
+
+func marshalIndent(v interface{}) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// paramError distinguishes a missing required parameter from one that was
+// present but failed validation, so MCP clients can react differently.
+type paramError struct {
+	Missing bool
+	Field   string
+	Reason  string
+}
+
+func (e *paramError) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("missing parameter %q", e.Field)
+	}
+	return fmt.Sprintf("invalid parameter %q: %s", e.Field, e.Reason)
+}
+
+func missingParam(field string) error {
+	return &paramError{Missing: true, Field: field}
+}
+
+func invalidParam(field, reason string) error {
+	return &paramError{Field: field, Reason: reason}
+}
+
+// validator is implemented by typed parameter structs that need more than
+// presence checking (e.g. URL scheme validation).
+type validator interface {
+	Validate() error
+}
+
+// parseArgs decodes request's arguments into dst (a pointer to a typed
+// parameter struct) and, if dst implements validator, runs its Validate
+// method. This is the single place handlers should go through instead of
+// manually type-asserting request.Params.Arguments.
+func parseArgs(request mcp.CallToolRequest, dst interface{}) error {
+	data, err := json.Marshal(request.Params.Arguments)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return err
+	}
+	if v, ok := dst.(validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// paramErrorResult renders a paramError (or any error) as a structured MCP
+// tool error result. A *paramError gets "missing_parameter" or
+// "invalid_parameter" as its code; anything else gets "unknown".
+func paramErrorResult(err error) *mcp.CallToolResult {
+	code := "unknown"
+	var pErr *paramError
+	if errors.As(err, &pErr) {
+		if pErr.Missing {
+			code = "missing_parameter"
+		} else {
+			code = "invalid_parameter"
+		}
+	}
+	return toolResultError(code, err.Error())
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func requestWithArgs(args map[string]interface{}) mcp.CallToolRequest {
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	return req
+}
+
+func TestParseArgsPlayStream(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr bool
+		missing bool
+	}{
+		{name: "missing url", args: map[string]interface{}{}, wantErr: true, missing: true},
+		{name: "invalid scheme", args: map[string]interface{}{"url": "ftp://x"}, wantErr: true},
+		{name: "valid https", args: map[string]interface{}{"url": "https://example.com/stream"}, wantErr: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var params playStreamParams
+			err := parseArgs(requestWithArgs(c.args), &params)
+			if c.wantErr && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.missing {
+				pe, ok := err.(*paramError)
+				if !ok || !pe.Missing {
+					t.Fatalf("expected a missing-param error, got %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func registerPrompts(s *server.MCPServer) {
+	s.AddPrompt(mcp.NewPrompt("build_playlist",
+		mcp.WithPromptDescription("Guides the model through building a playlist matching a mood/genre"),
+		mcp.WithArgument("mood", mcp.ArgumentDescription("Desired mood, e.g. \"chill\" or \"energetic\"")),
+		mcp.WithArgument("genre", mcp.ArgumentDescription("Desired genre, e.g. \"jazz\"")),
+		mcp.WithArgument("count", mcp.ArgumentDescription("Number of tracks to include")),
+	), buildPlaylistPrompt)
+}
+
+func buildPlaylistPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	mood := request.Params.Arguments["mood"]
+	genre := request.Params.Arguments["genre"]
+	count := request.Params.Arguments["count"]
+	if count == "" {
+		count = "10"
+	}
+
+	text := fmt.Sprintf(
+		"Build a %s-track playlist matching mood=%q genre=%q. "+
+			"Call search_advanced to find candidate tracks, then call create_playlist "+
+			"with the chosen track IDs in listening order.",
+		count, mood, genre)
+
+	return &mcp.GetPromptResult{
+		Description: "Build a playlist matching a mood/genre",
+		Messages: []mcp.PromptMessage{
+			{
+				Role:    mcp.RoleUser,
+				Content: mcp.TextContent{Type: "text", Text: text},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBuildPlaylistPromptRendersArguments(t *testing.T) {
+	request := mcp.GetPromptRequest{}
+	request.Params.Arguments = map[string]string{
+		"mood":  "chill",
+		"genre": "jazz",
+		"count": "5",
+	}
+
+	result, err := buildPlaylistPrompt(context.Background(), request)
+	if err != nil {
+		t.Fatalf("buildPlaylistPrompt: %v", err)
+	}
+	if len(result.Messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(result.Messages))
+	}
+	content, ok := result.Messages[0].Content.(mcp.TextContent)
+	if !ok {
+		t.Fatalf("message content is %T, want mcp.TextContent", result.Messages[0].Content)
+	}
+	for _, want := range []string{"5-track", "chill", "jazz", "search_advanced", "create_playlist"} {
+		if !strings.Contains(content.Text, want) {
+			t.Errorf("prompt text %q missing %q", content.Text, want)
+		}
+	}
+}
+
+func TestBuildPlaylistPromptDefaultsCount(t *testing.T) {
+	request := mcp.GetPromptRequest{}
+	request.Params.Arguments = map[string]string{"mood": "energetic"}
+
+	result, err := buildPlaylistPrompt(context.Background(), request)
+	if err != nil {
+		t.Fatalf("buildPlaylistPrompt: %v", err)
+	}
+	content := result.Messages[0].Content.(mcp.TextContent)
+	if !strings.Contains(content.Text, "10-track") {
+		t.Errorf("prompt text %q missing default count", content.Text)
+	}
+}
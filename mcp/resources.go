@@ -0,0 +1,242 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chew-z/itunes.vim/database"
+	"github.com/chew-z/itunes.vim/itunes"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func registerResources(s *server.MCPServer) {
+	s.AddResource(mcp.NewResource(
+		"itunes://database/playlists",
+		"Playlists",
+		mcp.WithResourceDescription("All user playlists in the library cache"),
+		mcp.WithMIMEType("application/json"),
+	), playlistsResourceHandler)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"itunes://playlist/{id}/tracks",
+		"Playlist tracks",
+		mcp.WithTemplateDescription("Tracks belonging to the playlist identified by its persistent ID"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), playlistTracksResourceHandler)
+
+	s.AddResource(mcp.NewResource(
+		"itunes://health",
+		"Health",
+		mcp.WithResourceDescription("Database and Apple Music bridge reachability, schema version, and track count"),
+		mcp.WithMIMEType("application/json"),
+	), healthResourceHandler)
+
+	s.AddResource(mcp.NewResource(
+		"itunes://database/stats",
+		"Stats",
+		mcp.WithResourceDescription("Detailed library statistics: row counts and rating distribution"),
+		mcp.WithMIMEType("application/json"),
+	), statsResourceHandler)
+
+	s.AddResource(mcp.NewResource(
+		"itunes://library/top",
+		"Top played",
+		mcp.WithResourceDescription("Most-played tracks and artists, by play count"),
+		mcp.WithMIMEType("application/json"),
+	), topPlayedResourceHandler)
+
+	s.AddResource(mcp.NewResource(
+		"itunes://library/playlist-tree",
+		"Playlist tree",
+		mcp.WithResourceDescription("Playlists and folders nested by parent, mirroring Apple Music's folder hierarchy"),
+		mcp.WithMIMEType("application/json"),
+	), playlistTreeResourceHandler)
+
+	s.AddResource(mcp.NewResource(
+		"itunes://library/recently-added",
+		"Recently added",
+		mcp.WithResourceDescription("The most recently added tracks, newest first"),
+		mcp.WithMIMEType("application/json"),
+	), recentlyAddedResourceHandler)
+
+	s.AddResource(mcp.NewResource(
+		"itunes://library/popular-searches",
+		"Popular searches",
+		mcp.WithResourceDescription("The most frequently searched queries, most popular first (empty unless ITUNES_SEARCH_LOG is enabled)"),
+		mcp.WithMIMEType("application/json"),
+	), popularSearchesResourceHandler)
+}
+
+func playlistTreeResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	tree, err := itunes.ListPlaylistTree()
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshalIndent(tree)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+func topPlayedResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	top, err := itunes.GetTopPlayed(itunes.DefaultTopLimit)
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshalIndent(top)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+func recentlyAddedResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	tracks, err := itunes.GetRecentlyAdded(itunes.DefaultTopLimit)
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshalIndent(tracks)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+func popularSearchesResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	popular, err := itunes.GetPopularSearches(itunes.DefaultTopLimit)
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshalIndent(popular)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+// detailedStats is the itunes://database/stats resource body: row counts
+// plus the rating distribution and starred/unstarred split.
+type detailedStats struct {
+	*database.Stats
+	Ratings *itunes.RatingStats `json:"ratings"`
+}
+
+func statsResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	libraryStats, err := itunes.GetLibraryStats()
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := itunes.GetRatingStats()
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshalIndent(detailedStats{Stats: libraryStats, Ratings: ratings})
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+func healthResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	data, err := marshalIndent(itunes.GetHealth(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+func playlistsResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	page, err := itunes.ListPlaylists(nil, false, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshalIndent(page.Playlists)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+// playlistIDFromURI extracts the {id} segment from an
+// itunes://playlist/{id}/tracks URI.
+func playlistIDFromURI(uri string) (string, error) {
+	const prefix = "itunes://playlist/"
+	const suffix = "/tracks"
+	if !strings.HasPrefix(uri, prefix) || !strings.HasSuffix(uri, suffix) {
+		return "", fmt.Errorf("malformed playlist tracks URI: %s", uri)
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(uri, prefix), suffix)
+	if id == "" {
+		return "", fmt.Errorf("missing playlist id in URI: %s", uri)
+	}
+	return id, nil
+}
+
+func playlistTracksResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	id, err := playlistIDFromURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := itunes.GetPlaylistTracks(id)
+	if err != nil {
+		return nil, fmt.Errorf("playlist %q not found: %w", id, err)
+	}
+
+	data, err := marshalIndent(tracks)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSearchAdvancedHandlerProjectsFields(t *testing.T) {
+	withSeededTrack(t)
+
+	result, err := searchAdvancedHandler(context.Background(), requestWithArgs(map[string]interface{}{
+		"query":  "Blue in Green",
+		"fields": "name,artist",
+	}))
+	if err != nil {
+		t.Fatalf("searchAdvancedHandler: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent)
+	if !strings.Contains(text.Text, "Blue in Green") || !strings.Contains(text.Text, "Miles Davis") {
+		t.Fatalf("result %q missing requested fields", text.Text)
+	}
+	if strings.Contains(text.Text, "Jazz") {
+		t.Fatalf("result %q leaked the genre field, which wasn't requested", text.Text)
+	}
+}
+
+func TestSearchAdvancedHandlerWritesOutputFile(t *testing.T) {
+	withSeededTrack(t)
+	path := filepath.Join(t.TempDir(), "results.json")
+
+	_, err := searchAdvancedHandler(context.Background(), requestWithArgs(map[string]interface{}{
+		"query":       "Blue in Green",
+		"output_file": path,
+	}))
+	if err != nil {
+		t.Fatalf("searchAdvancedHandler: %v", err)
+	}
+	data, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatalf("ReadFile: %v", readErr)
+	}
+	if !strings.Contains(string(data), "Blue in Green") {
+		t.Fatalf("output file %q missing the matched track", data)
+	}
+}
+
+func TestSearchAdvancedHandlerDebugIncludesMeta(t *testing.T) {
+	withSeededTrack(t)
+
+	plain, err := searchAdvancedHandler(context.Background(), requestWithArgs(map[string]interface{}{
+		"query": "Blue in Green",
+	}))
+	if err != nil {
+		t.Fatalf("searchAdvancedHandler: %v", err)
+	}
+	if strings.Contains(plain.Content[0].(mcp.TextContent).Text, "_meta") {
+		t.Fatal("expected no _meta field without debug")
+	}
+
+	debug, err := searchAdvancedHandler(context.Background(), requestWithArgs(map[string]interface{}{
+		"query": "Blue in Green",
+		"debug": true,
+	}))
+	if err != nil {
+		t.Fatalf("searchAdvancedHandler: %v", err)
+	}
+	text := debug.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, `"_meta"`) || !strings.Contains(text, `"method"`) {
+		t.Fatalf("result %q missing _meta with debug=true", text)
+	}
+}
+
+func TestSearchAdvancedHandlerStrictFieldsRejectsUnknown(t *testing.T) {
+	withSeededTrack(t)
+
+	result, err := searchAdvancedHandler(context.Background(), requestWithArgs(map[string]interface{}{
+		"query":         "Blue in Green",
+		"fields":        "bogus_field",
+		"strict_fields": true,
+	}))
+	if err != nil {
+		t.Fatalf("searchAdvancedHandler: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected an error result for an unknown field with strict_fields set")
+	}
+}
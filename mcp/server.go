@@ -0,0 +1,24 @@
+// Package mcp exposes the itunes package over the Model Context Protocol
+// so LLM clients can search, browse, and control playback.
+package mcp
+
+import (
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	serverName    = "itunes-mcp"
+	serverVersion = "0.1.0"
+)
+
+// NewServer builds the MCP server with every tool, resource, and prompt
+// registered.
+func NewServer() *server.MCPServer {
+	s := server.NewMCPServer(serverName, serverVersion)
+
+	registerTools(s)
+	registerResources(s)
+	registerPrompts(s)
+
+	return s
+}
@@ -0,0 +1,935 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chew-z/itunes.vim/database"
+	"github.com/chew-z/itunes.vim/itunes"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// playParams is the typed, validated argument shape for play.
+type playParams struct {
+	Query string `json:"query"`
+}
+
+func (p playParams) Validate() error {
+	if strings.TrimSpace(p.Query) == "" {
+		return missingParam("query")
+	}
+	return nil
+}
+
+// playStreamParams is the typed, validated argument shape for play_stream.
+type playStreamParams struct {
+	URL string `json:"url"`
+}
+
+func (p playStreamParams) Validate() error {
+	if p.URL == "" {
+		return missingParam("url")
+	}
+	// Delegate the accepted-scheme check to itunes.NormalizeStreamURL
+	// instead of keeping a second, independent copy of it here: it's the
+	// same function playStreamHandler calls to actually play the URL, so
+	// the two can't drift on which schemes are acceptable.
+	if _, err := itunes.NormalizeStreamURL(p.URL); err != nil {
+		return invalidParam("url", "must be an http(s):// or itmss:// URL")
+	}
+	return nil
+}
+
+// searchStationsParams is the typed argument shape for search_stations.
+// query is optional: an empty query lists all active stations.
+type searchStationsParams struct {
+	Query string `json:"query"`
+}
+
+// searchAdvancedParams is the typed argument shape for search_advanced,
+// layering database.SearchFilters and optional grouping on top of a plain
+// text query.
+type searchAdvancedParams struct {
+	Query          string `json:"query"`
+	Artist         string `json:"artist"`
+	ArtistPrefix   string `json:"artist_prefix"`
+	Album          string `json:"album"`
+	Genre          string `json:"genre"`
+	Kind           string `json:"kind"`
+	MinRating      int    `json:"min_rating"`
+	Disliked       *bool  `json:"disliked"`
+	DownloadedOnly *bool  `json:"downloaded_only"`
+	GroupBy        string `json:"group_by"`
+	Fields         string `json:"fields"`
+	StrictFields   bool   `json:"strict_fields"`
+	OutputFile     string `json:"output_file"`
+	Format         string `json:"format"`
+	Debug          bool   `json:"debug"`
+	ExactMatch     bool   `json:"exact_match"`
+	ExactName      bool   `json:"exact_name"`
+}
+
+func (p searchAdvancedParams) Validate() error {
+	if p.GroupBy != "" && p.GroupBy != itunes.GroupByAlbum && p.GroupBy != itunes.GroupByArtist {
+		return invalidParam("group_by", `must be "album" or "artist"`)
+	}
+	if p.StrictFields {
+		if err := itunes.ValidateFields(splitFields(p.Fields)); err != nil {
+			return invalidParam("fields", err.Error())
+		}
+	}
+	if p.Format != "" && p.Format != itunes.ExportFormatJSON && p.Format != itunes.ExportFormatCSV {
+		return invalidParam("format", `must be "json" or "csv"`)
+	}
+	if p.Format != "" && p.OutputFile == "" {
+		return invalidParam("format", "requires output_file")
+	}
+	return nil
+}
+
+// searchAlbumsParams is the typed argument shape for search_albums.
+type searchAlbumsParams struct {
+	Query      string `json:"query"`
+	Artist     string `json:"artist"`
+	Genre      string `json:"genre"`
+	ExactMatch bool   `json:"exact_match"`
+}
+
+// searchArtistsParams is the typed argument shape for search_artists.
+type searchArtistsParams struct {
+	Query      string `json:"query"`
+	Genre      string `json:"genre"`
+	ExactMatch bool   `json:"exact_match"`
+}
+
+// flattenGroups concatenates every group's tracks back into the flat list
+// SearchTracksGrouped built them from, for output_file export (which
+// always writes the full matched set regardless of group_by/fields).
+func flattenGroups(groups []itunes.TrackGroup) []itunes.Track {
+	var tracks []itunes.Track
+	for _, g := range groups {
+		tracks = append(tracks, g.Tracks...)
+	}
+	return tracks
+}
+
+// splitFields parses search_advanced's comma-separated fields parameter,
+// trimming whitespace and dropping empty entries.
+func splitFields(s string) []string {
+	parts := strings.Split(s, ",")
+	fields := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			fields = append(fields, p)
+		}
+	}
+	return fields
+}
+
+// bulkUpdateParams is the typed argument shape for bulk_update: the same
+// filter fields as searchAdvancedParams narrow which tracks are touched,
+// and exactly one of Starred/Rating says what to set on them.
+type bulkUpdateParams struct {
+	Artist    string `json:"artist"`
+	Album     string `json:"album"`
+	Genre     string `json:"genre"`
+	MinRating int    `json:"min_rating"`
+	Disliked  *bool  `json:"disliked"`
+	Starred   *bool  `json:"starred"`
+	Rating    *int   `json:"rating"`
+	DryRun    bool   `json:"dry_run"`
+}
+
+func (p bulkUpdateParams) Validate() error {
+	if p.Starred == nil && p.Rating == nil {
+		return invalidParam("starred", "must set starred or rating")
+	}
+	if p.Starred != nil && p.Rating != nil {
+		return invalidParam("starred", "set only one of starred or rating per call")
+	}
+	if p.Rating != nil && (*p.Rating < 0 || *p.Rating > 100) {
+		return invalidParam("rating", "must be 0-100")
+	}
+	return nil
+}
+
+// editTrackParams is the typed argument shape for edit_track: TrackID says
+// which track to change, and at least one of Name/Artist/Album/Genre says
+// what to change on it.
+type editTrackParams struct {
+	TrackID string  `json:"track_id"`
+	Name    *string `json:"name"`
+	Artist  *string `json:"artist"`
+	Album   *string `json:"album"`
+	Genre   *string `json:"genre"`
+}
+
+// searchItunesParams is the typed argument shape for search_itunes.
+// TimeoutMs is optional; when unset the search runs with no deadline.
+type searchItunesParams struct {
+	Query     string `json:"query"`
+	TimeoutMs int    `json:"timeout_ms"`
+}
+
+func (p editTrackParams) Validate() error {
+	if p.TrackID == "" {
+		return missingParam("track_id")
+	}
+	if p.Name == nil && p.Artist == nil && p.Album == nil && p.Genre == nil {
+		return invalidParam("name", "must set at least one of name, artist, album, or genre")
+	}
+	return nil
+}
+
+func registerTools(s *server.MCPServer) {
+	s.AddTool(mcp.NewTool("search_itunes",
+		mcp.WithDescription("Search the local iTunes/Music library by free-text query"),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Search text")),
+		mcp.WithNumber("timeout_ms", mcp.Description("Cancel the search and return partial results after this many milliseconds; omit or 0 for no deadline")),
+	), searchItunesHandler)
+
+	s.AddTool(mcp.NewTool("play_track",
+		mcp.WithDescription("Play a track from a playlist"),
+		mcp.WithString("playlist", mcp.Required()),
+		mcp.WithString("track", mcp.Required()),
+	), playTrackHandler)
+
+	s.AddTool(mcp.NewTool("play",
+		mcp.WithDescription("Play whatever best matches a free-text query: a playlist name, or an artist/album/track to search for"),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Playlist name, or track/artist/album text to search for")),
+	), playHandler)
+
+	s.AddTool(mcp.NewTool("play_stream",
+		mcp.WithDescription("Play an internet radio/stream URL"),
+		mcp.WithString("url", mcp.Required()),
+	), playStreamHandler)
+
+	s.AddTool(mcp.NewTool("play_url",
+		mcp.WithDescription("Play an Apple Music catalog share link (song, album, or playlist)"),
+		mcp.WithString("url", mcp.Required()),
+	), playURLHandler)
+
+	s.AddTool(mcp.NewTool("more_like_this",
+		mcp.WithDescription("Find other tracks by the same artist or from the same album as whatever is currently playing"),
+		mcp.WithString("mode", mcp.Description(`"artist" or "album" (default "artist")`)),
+	), moreLikeThisHandler)
+
+	s.AddTool(mcp.NewTool("search_advanced",
+		mcp.WithDescription("Search the library with field filters and optional grouping by album or artist"),
+		mcp.WithString("query", mcp.Description("Search text")),
+		mcp.WithString("artist", mcp.Description("Restrict to this artist")),
+		mcp.WithString("artist_prefix", mcp.Description(`Restrict to artists whose name starts with this value (anchored), unlike "artist" which matches anywhere in the name`)),
+		mcp.WithString("album", mcp.Description("Restrict to this album")),
+		mcp.WithString("genre", mcp.Description("Restrict to this genre")),
+		mcp.WithString("kind", mcp.Description(`Restrict to this exact track_kind, e.g. "Internet audio stream" or "MPEG audio file"; see list_kinds`)),
+		mcp.WithNumber("min_rating", mcp.Description("Minimum star rating (0-100)")),
+		mcp.WithBoolean("disliked", mcp.Description("Filter to only (true) or exclude (false) disliked tracks; omit for no filter")),
+		mcp.WithBoolean("downloaded_only", mcp.Description("Filter to only (true) or exclude (false) tracks available offline; omit for no filter")),
+		mcp.WithString("group_by", mcp.Description(`Group results by "album" or "artist"`)),
+		mcp.WithString("fields", mcp.Description("Comma-separated list of track fields to return; omit for all fields")),
+		mcp.WithBoolean("strict_fields", mcp.Description("Reject unknown field names in fields instead of silently dropping them")),
+		mcp.WithString("output_file", mcp.Description("Also save the full matched track set to this file path")),
+		mcp.WithString("format", mcp.Description(`File format for output_file: "json" (default) or "csv"`)),
+		mcp.WithBoolean("debug", mcp.Description("Include a _meta object with search duration, method (fts/like), and cache hit")),
+		mcp.WithBoolean("exact_match", mcp.Description(`Case- and accent-sensitive matching, e.g. to distinguish "cafe" from "café"`)),
+		mcp.WithBoolean("exact_name", mcp.Description(`Match query against the track title exactly (case-insensitive) instead of as a substring, e.g. to find "So What" without also matching "What"`)),
+	), searchAdvancedHandler)
+
+	s.AddTool(mcp.NewTool("search_albums",
+		mcp.WithDescription("Search the library for distinct albums matching a query, instead of individual tracks"),
+		mcp.WithString("query", mcp.Description("Search text")),
+		mcp.WithString("artist", mcp.Description("Restrict to this artist")),
+		mcp.WithString("genre", mcp.Description("Restrict to this genre")),
+		mcp.WithBoolean("exact_match", mcp.Description(`Case- and accent-sensitive matching, e.g. to distinguish "cafe" from "café"`)),
+	), searchAlbumsHandler)
+
+	s.AddTool(mcp.NewTool("search_artists",
+		mcp.WithDescription("Search the library for distinct artists matching a query, instead of individual tracks"),
+		mcp.WithString("query", mcp.Description("Search text")),
+		mcp.WithString("genre", mcp.Description("Restrict to this genre")),
+		mcp.WithBoolean("exact_match", mcp.Description(`Case- and accent-sensitive matching, e.g. to distinguish "cafe" from "café"`)),
+	), searchArtistsHandler)
+
+	s.AddTool(mcp.NewTool("search_all",
+		mcp.WithDescription("Search local tracks and cached radio stations together"),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Search text")),
+	), searchAllHandler)
+
+	s.AddTool(mcp.NewTool("search_stations",
+		mcp.WithDescription("Search cached internet radio stations"),
+		mcp.WithString("query"),
+	), searchStationsHandler)
+
+	s.AddTool(mcp.NewTool("list_station_genres",
+		mcp.WithDescription("List radio station genres with station counts, most popular first"),
+	), listStationGenresHandler)
+
+	s.AddTool(mcp.NewTool("list_kinds",
+		mcp.WithDescription("List distinct track kinds (e.g. music file, internet audio stream) with counts, most common first; use with search_advanced's kind parameter to separate music, podcasts, and streams"),
+	), listKindsHandler)
+
+	s.AddTool(mcp.NewTool("top_tracks",
+		mcp.WithDescription("List the most-played tracks, highest play count first"),
+		mcp.WithNumber("limit", mcp.Description("Max tracks to return (default 20)")),
+	), topTracksHandler)
+
+	s.AddTool(mcp.NewTool("top_artists",
+		mcp.WithDescription("List the artists with the highest summed play count, highest first"),
+		mcp.WithNumber("limit", mcp.Description("Max artists to return (default 20)")),
+	), topArtistsHandler)
+
+	s.AddTool(mcp.NewTool("whats_new",
+		mcp.WithDescription("List tracks added by the most recent library refresh"),
+	), whatsNewHandler)
+
+	s.AddTool(mcp.NewTool("recently_added",
+		mcp.WithDescription("List the most recently added tracks, newest first"),
+		mcp.WithNumber("limit", mcp.Description("Max tracks to return (default 20)")),
+	), recentlyAddedHandler)
+
+	s.AddTool(mcp.NewTool("list_playlists",
+		mcp.WithDescription("List the user's playlists"),
+		mcp.WithBoolean("smart", mcp.Description("Filter to only (true) or exclude (false) smart playlists; omit for no filter")),
+		mcp.WithBoolean("include_special", mcp.Description("Also include system playlists like \"Recently Added\" (default: user playlists only)")),
+		mcp.WithNumber("limit", mcp.Description("Max playlists to return per page; omit or 0 for every playlist")),
+		mcp.WithNumber("offset", mcp.Description("Playlists to skip before the page starts; used with limit")),
+	), listPlaylistsHandler)
+
+	s.AddTool(mcp.NewTool("get_playlist_tracks",
+		mcp.WithDescription("List the tracks in a playlist, paged; use this instead of the itunes://playlist/{id}/tracks resource for large playlists"),
+		mcp.WithString("playlist_id", mcp.Required(), mcp.Description("Persistent ID of the playlist")),
+		mcp.WithNumber("limit", mcp.Description("Max tracks to return per page; omit or 0 for every track")),
+		mcp.WithNumber("offset", mcp.Description("Tracks to skip before the page starts; used with limit")),
+	), getPlaylistTracksHandler)
+
+	s.AddTool(mcp.NewTool("refresh_library",
+		mcp.WithDescription("Refresh the local library cache from Apple Music"),
+	), refreshLibraryHandler)
+
+	s.AddTool(mcp.NewTool("refresh_playlists",
+		mcp.WithDescription("Reconcile playlist membership only, without rescanning track metadata; much faster than refresh_library for playlist-only edits"),
+	), refreshPlaylistsHandler)
+
+	s.AddTool(mcp.NewTool("clear_cache",
+		mcp.WithDescription("Clear the cached search results, so the next search reflects any recent write"),
+	), clearCacheHandler)
+
+	s.AddTool(mcp.NewTool("optimize_database",
+		mcp.WithDescription("Run VACUUM and ANALYZE against the library cache to reclaim space after large deletes/migrations; reports database size before and after"),
+	), optimizeDatabaseHandler)
+
+	s.AddTool(mcp.NewTool("bulk_update",
+		mcp.WithDescription("Set starred or rating on every track matching a filter, e.g. to star a whole artist or genre at once"),
+		mcp.WithString("artist", mcp.Description("Restrict to this artist")),
+		mcp.WithString("album", mcp.Description("Restrict to this album")),
+		mcp.WithString("genre", mcp.Description("Restrict to this genre")),
+		mcp.WithNumber("min_rating", mcp.Description("Minimum star rating (0-100)")),
+		mcp.WithBoolean("disliked", mcp.Description("Filter to only (true) or exclude (false) disliked tracks; omit for no filter")),
+		mcp.WithBoolean("starred", mcp.Description("Set this starred value on every matching track")),
+		mcp.WithNumber("rating", mcp.Description("Set this rating (0-100) on every matching track")),
+		mcp.WithBoolean("dry_run", mcp.Description("Preview how many tracks would be touched without changing anything")),
+	), bulkUpdateHandler)
+
+	s.AddTool(mcp.NewTool("now_playing_compact",
+		mcp.WithDescription("Get a single-line now-playing status suitable for menu bars and prompts"),
+	), nowPlayingCompactHandler)
+
+	s.AddTool(mcp.NewTool("get_track",
+		mcp.WithDescription("Get a single track's cached details (name, artist, album, genre, rating, play count) by persistent ID; the playlists containing the track are not included"),
+		mcp.WithString("track_id", mcp.Required(), mcp.Description("Persistent ID of the track")),
+	), getTrackHandler)
+
+	s.AddTool(mcp.NewTool("get_lyrics",
+		mcp.WithDescription("Get the lyrics for a track by persistent ID, or the currently playing track if omitted"),
+		mcp.WithString("track_id", mcp.Description("Persistent ID of the track; omit to use the current track")),
+	), getLyricsHandler)
+
+	s.AddTool(mcp.NewTool("get_crossfade",
+		mcp.WithDescription("Get Apple Music's crossfade preference"),
+	), getCrossfadeHandler)
+
+	s.AddTool(mcp.NewTool("set_crossfade",
+		mcp.WithDescription("Enable/disable crossfade and set its duration in seconds (1-12)"),
+		mcp.WithBoolean("enabled", mcp.Required()),
+		mcp.WithNumber("seconds", mcp.Description("Crossfade duration in seconds, 1-12; ignored when enabled=false")),
+	), setCrossfadeHandler)
+
+	s.AddTool(mcp.NewTool("set_loved",
+		mcp.WithDescription(`Set/clear a track's "loved" state in Apple Music`),
+		mcp.WithString("track_id", mcp.Required(), mcp.Description("Persistent ID of the track")),
+		mcp.WithBoolean("loved", mcp.Required()),
+	), setLovedHandler)
+
+	s.AddTool(mcp.NewTool("set_disliked",
+		mcp.WithDescription(`Set/clear a track's "disliked" state in Apple Music; excludable from recommendations via search_advanced`),
+		mcp.WithString("track_id", mcp.Required(), mcp.Description("Persistent ID of the track")),
+		mcp.WithBoolean("disliked", mcp.Required()),
+	), setDislikedHandler)
+
+	s.AddTool(mcp.NewTool("edit_track",
+		mcp.WithDescription("Edit a track's name, artist, album, or genre in Apple Music and the local cache"),
+		mcp.WithString("track_id", mcp.Required(), mcp.Description("Persistent ID of the track")),
+		mcp.WithString("name", mcp.Description("New track name")),
+		mcp.WithString("artist", mcp.Description("New artist name")),
+		mcp.WithString("album", mcp.Description("New album name")),
+		mcp.WithString("genre", mcp.Description("New genre")),
+	), editTrackHandler)
+
+	s.AddTool(mcp.NewTool("reorder_playlist",
+		mcp.WithDescription("Move a track to a new position within a playlist"),
+		mcp.WithString("playlist_id", mcp.Required(), mcp.Description("Persistent ID of the playlist")),
+		mcp.WithString("track_id", mcp.Required(), mcp.Description("Persistent ID of the track to move")),
+		mcp.WithNumber("position", mcp.Required(), mcp.Description("1-based target position")),
+	), reorderPlaylistHandler)
+
+	s.AddTool(mcp.NewTool("reconnect",
+		mcp.WithDescription("Close and reopen the library database, picking up a replacement DB file without restarting"),
+	), reconnectHandler)
+
+	s.AddTool(mcp.NewTool("ping",
+		mcp.WithDescription("Check database and Apple Music bridge health"),
+	), pingHandler)
+}
+
+func argString(request mcp.CallToolRequest, key string) string {
+	if v, ok := request.Params.Arguments[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// searchItunesResult is the search_itunes response shape when a timeout_ms
+// budget cut the search short: Tracks holds whatever was found before the
+// deadline, and Warning tells the caller the result set is incomplete
+// rather than silently returning a partial list that looks exhaustive.
+type searchItunesResult struct {
+	Tracks  []itunes.Track `json:"tracks"`
+	Warning string         `json:"warning"`
+}
+
+func searchItunesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params searchItunesParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+
+	var tracks []itunes.Track
+	var err error
+	if params.TimeoutMs > 0 {
+		tracks, err = itunes.SearchTracksWithTimeout(params.Query, nil, time.Duration(params.TimeoutMs)*time.Millisecond)
+	} else {
+		tracks, err = itunes.SearchTracksFromDatabase(params.Query, nil)
+	}
+
+	var itErr *itunes.ITunesError
+	if errors.As(err, &itErr) && itErr.Kind == itunes.ErrTimeout {
+		return textResult(searchItunesResult{
+			Tracks:  tracks,
+			Warning: fmt.Sprintf("search timed out after %dms; returning %d partial result(s)", params.TimeoutMs, len(tracks)),
+		})
+	}
+	if err == itunes.ErrLibraryEmpty {
+		return mcp.NewToolResultText("library empty, run refresh_library"), nil
+	}
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(tracks)
+}
+
+func searchAdvancedHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params searchAdvancedParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	filters := &database.SearchFilters{
+		Artist:         params.Artist,
+		ArtistPrefix:   params.ArtistPrefix,
+		Album:          params.Album,
+		Genre:          params.Genre,
+		Kind:           params.Kind,
+		MinRating:      params.MinRating,
+		Disliked:       params.Disliked,
+		DownloadedOnly: params.DownloadedOnly,
+		ExactMatch:     params.ExactMatch,
+		ExactName:      params.ExactName,
+	}
+	result, err := itunes.SearchTracksGrouped(params.Query, filters, params.GroupBy)
+	if err == itunes.ErrLibraryEmpty {
+		return mcp.NewToolResultText("library empty, run refresh_library"), nil
+	}
+	if err != nil {
+		return errorResult(err)
+	}
+	if params.OutputFile != "" {
+		if err := itunes.WriteTracksToFile(params.OutputFile, flattenGroups(result.Groups), params.Format); err != nil {
+			return errorResult(err)
+		}
+	}
+	var response interface{} = result
+	if fields := splitFields(params.Fields); len(fields) > 0 {
+		projected, err := result.Project(fields)
+		if err != nil {
+			return errorResult(err)
+		}
+		response = projected
+	}
+	if params.Debug {
+		response = withSearchMeta(response)
+	}
+	return textResult(response)
+}
+
+func searchAlbumsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params searchAlbumsParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	filters := &database.SearchFilters{Artist: params.Artist, Genre: params.Genre, ExactMatch: params.ExactMatch}
+	albums, err := itunes.SearchAlbums(params.Query, filters)
+	if err == itunes.ErrLibraryEmpty {
+		return mcp.NewToolResultText("library empty, run refresh_library"), nil
+	}
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(albums)
+}
+
+func searchArtistsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params searchArtistsParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	filters := &database.SearchFilters{Genre: params.Genre, ExactMatch: params.ExactMatch}
+	artists, err := itunes.SearchArtists(params.Query, filters)
+	if err == itunes.ErrLibraryEmpty {
+		return mcp.NewToolResultText("library empty, run refresh_library"), nil
+	}
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(artists)
+}
+
+// searchMeta is search_advanced's optional "_meta" field, surfacing the
+// underlying SearchManager's per-query diagnostics (method, timing, cache
+// hit) when a caller asks for it via the debug param.
+type searchMeta struct {
+	DurationMS float64 `json:"duration_ms"`
+	Method     string  `json:"method"`
+	CacheHit   bool    `json:"cache_hit"`
+}
+
+type searchResultWithMeta struct {
+	Result interface{} `json:"result"`
+	Meta   searchMeta  `json:"_meta"`
+}
+
+func withSearchMeta(result interface{}) searchResultWithMeta {
+	m := itunes.LastSearchMetrics()
+	return searchResultWithMeta{
+		Result: result,
+		Meta: searchMeta{
+			DurationMS: float64(m.Duration.Microseconds()) / 1000,
+			Method:     m.Method,
+			CacheHit:   m.CacheHit,
+		},
+	}
+}
+
+// playResult is the play tool's response: the chosen PlayTarget alongside
+// the resulting PlayResult, so a client can see what query resolved to
+// without a separate round trip.
+type playResult struct {
+	Resolved *itunes.PlayTarget `json:"resolved"`
+	Result   *itunes.PlayResult `json:"result"`
+}
+
+func playHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params playParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	target, err := itunes.ResolvePlayTarget(params.Query)
+	if err != nil {
+		return errorResult(err)
+	}
+	result, err := itunes.PlayResolved(ctx, target)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(playResult{Resolved: target, Result: result})
+}
+
+func playTrackHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	playlist := argString(request, "playlist")
+	track := argString(request, "track")
+	result, err := itunes.PlayPlaylistTrackWithStatus(ctx, playlist, track)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(result)
+}
+
+func playStreamHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params playStreamParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	result, err := itunes.PlayStreamURL(ctx, params.URL)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(result)
+}
+
+func playURLHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	url := argString(request, "url")
+	result, err := itunes.PlayAppleMusicURL(ctx, url)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(result)
+}
+
+func moreLikeThisHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	mode := argString(request, "mode")
+	if mode == "" {
+		mode = "artist"
+	}
+	tracks, err := itunes.SearchLikeNowPlaying(ctx, itunes.LikeNowPlayingMode(mode))
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(tracks)
+}
+
+func searchAllHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := argString(request, "query")
+	result, err := itunes.UnifiedSearch(query)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(result)
+}
+
+// stationResult mirrors database.RadioStation but puts Homepage right
+// after Name, ahead of URL/description/genre, so an LLM client scanning
+// search_stations results sees it without digging — stations without a
+// homepage still round-trip it as "".
+type stationResult struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Homepage    string `json:"homepage"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	Genre       string `json:"genre,omitempty"`
+}
+
+func newStationResult(s database.RadioStation) stationResult {
+	return stationResult{
+		ID:          s.ID,
+		Name:        s.Name,
+		Homepage:    s.Homepage,
+		URL:         s.URL,
+		Description: s.Description,
+		Genre:       s.Genre,
+	}
+}
+
+func searchStationsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params searchStationsParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	stations, err := itunes.SearchStations(params.Query, &database.RadioStationFilters{ActiveOnly: true}, 50)
+	if err != nil {
+		return errorResult(err)
+	}
+	results := make([]stationResult, len(stations))
+	for i, s := range stations {
+		results[i] = newStationResult(s)
+	}
+	return textResult(results)
+}
+
+type topParams struct {
+	Limit int `json:"limit"`
+}
+
+func topTracksHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params topParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	top, err := itunes.GetTopPlayed(params.Limit)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(top.Tracks)
+}
+
+func topArtistsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params topParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	top, err := itunes.GetTopPlayed(params.Limit)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(top.Artists)
+}
+
+func listStationGenresHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	genres, err := itunes.ListStationGenres()
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(genres)
+}
+
+func listKindsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kinds, err := itunes.ListTrackKinds()
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(kinds)
+}
+
+func whatsNewHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tracks, err := itunes.GetTracksAddedSinceLastRefresh()
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(tracks)
+}
+
+func recentlyAddedHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params topParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	tracks, err := itunes.GetRecentlyAdded(params.Limit)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(tracks)
+}
+
+// listPlaylistsParams is the typed argument shape for list_playlists. Limit
+// and Offset default to 0, which returns every matching playlist unpaged.
+type listPlaylistsParams struct {
+	Smart          *bool `json:"smart"`
+	IncludeSpecial bool  `json:"include_special"`
+	Limit          int   `json:"limit"`
+	Offset         int   `json:"offset"`
+}
+
+func listPlaylistsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params listPlaylistsParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	page, err := itunes.ListPlaylists(params.Smart, params.IncludeSpecial, params.Limit, params.Offset)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(page)
+}
+
+// getPlaylistTracksParams is the typed argument shape for
+// get_playlist_tracks. Limit and Offset default to 0, which returns every
+// track in the playlist unpaged.
+type getPlaylistTracksParams struct {
+	PlaylistID string `json:"playlist_id"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+}
+
+func getPlaylistTracksHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params getPlaylistTracksParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	page, err := itunes.GetPlaylistTracksPage(params.PlaylistID, params.Limit, params.Offset)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(page)
+}
+
+func refreshLibraryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	stats, err := itunes.RefreshLibraryCache(ctx)
+	if err != nil {
+		return errorResult(err)
+	}
+	msg := fmt.Sprintf("refreshed library: %d tracks, %d playlists", stats.TrackCount, stats.PlaylistCount)
+	if stats.SkippedTracks > 0 {
+		msg += fmt.Sprintf(" (%d track(s) skipped, e.g. DRM/cloud-only items)", stats.SkippedTracks)
+	}
+	return mcp.NewToolResultText(msg), nil
+}
+
+func refreshPlaylistsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	stats, err := itunes.RefreshPlaylistsOnly(ctx)
+	if err != nil {
+		return errorResult(err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("refreshed %d playlists", stats.PlaylistCount)), nil
+}
+
+func clearCacheHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	itunes.ClearSearchCache()
+	return mcp.NewToolResultText("search cache cleared"), nil
+}
+
+func optimizeDatabaseHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	result, err := itunes.OptimizeDatabase()
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(result)
+}
+
+func bulkUpdateHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params bulkUpdateParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	filters := &database.SearchFilters{
+		Artist:    params.Artist,
+		Album:     params.Album,
+		Genre:     params.Genre,
+		MinRating: params.MinRating,
+		Disliked:  params.Disliked,
+	}
+	var (
+		result *database.BulkUpdateResult
+		err    error
+	)
+	if params.Starred != nil {
+		result, err = itunes.BulkSetStarred(filters, *params.Starred, params.DryRun)
+	} else {
+		result, err = itunes.BulkSetRating(filters, *params.Rating, params.DryRun)
+	}
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(result)
+}
+
+func nowPlayingCompactHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	line, err := itunes.GetNowPlayingCompact(ctx)
+	if err != nil {
+		return errorResult(err)
+	}
+	return mcp.NewToolResultText(line), nil
+}
+
+func getTrackHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	trackID := argString(request, "track_id")
+	track, err := itunes.GetTrackByPersistentID(trackID)
+	if err == itunes.ErrNoTracksFound {
+		return mcp.NewToolResultText("track not found"), nil
+	}
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(track)
+}
+
+func getLyricsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	trackID := argString(request, "track_id")
+
+	var (
+		lyrics string
+		err    error
+	)
+	if trackID == "" {
+		lyrics, err = itunes.GetCurrentLyrics(ctx)
+	} else {
+		lyrics, err = itunes.GetLyrics(ctx, trackID)
+	}
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(map[string]string{"lyrics": lyrics})
+}
+
+func getCrossfadeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	settings, err := itunes.GetCrossfadeSettings(ctx)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(settings)
+}
+
+func setCrossfadeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	enabled, _ := request.Params.Arguments["enabled"].(bool)
+	seconds := 0
+	if v, ok := request.Params.Arguments["seconds"].(float64); ok {
+		seconds = int(v)
+	}
+	settings, err := itunes.SetCrossfade(ctx, enabled, seconds)
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(settings)
+}
+
+func setLovedHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	trackID := argString(request, "track_id")
+	loved, _ := request.Params.Arguments["loved"].(bool)
+	if err := itunes.SetTrackLoved(ctx, trackID, loved); err != nil {
+		return errorResult(err)
+	}
+	return mcp.NewToolResultText("updated"), nil
+}
+
+func setDislikedHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	trackID := argString(request, "track_id")
+	disliked, _ := request.Params.Arguments["disliked"].(bool)
+	if err := itunes.SetTrackDisliked(ctx, trackID, disliked); err != nil {
+		return errorResult(err)
+	}
+	return mcp.NewToolResultText("updated"), nil
+}
+
+func editTrackHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params editTrackParams
+	if err := parseArgs(request, &params); err != nil {
+		return paramErrorResult(err), nil
+	}
+	edit := itunes.TrackEdit{Name: params.Name, Artist: params.Artist, Album: params.Album, Genre: params.Genre}
+	if err := itunes.UpdateTrackMetadata(ctx, params.TrackID, edit); err != nil {
+		return errorResult(err)
+	}
+	return mcp.NewToolResultText("updated"), nil
+}
+
+func reorderPlaylistHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	playlistID := argString(request, "playlist_id")
+	trackID := argString(request, "track_id")
+	position, _ := request.Params.Arguments["position"].(float64)
+	if err := itunes.ReorderPlaylistTrack(playlistID, trackID, int(position)); err != nil {
+		return errorResult(err)
+	}
+	return mcp.NewToolResultText("playlist reordered"), nil
+}
+
+func reconnectHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := itunes.ReopenDatabase(); err != nil {
+		return errorResult(err)
+	}
+	return mcp.NewToolResultText("database reconnected"), nil
+}
+
+func pingHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return textResult(itunes.GetHealth(ctx))
+}
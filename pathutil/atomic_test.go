@@ -0,0 +1,48 @@
+package pathutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomicWritesContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+
+	if err := WriteFileAtomic(path, []byte("[]"), 0o644); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "[]" {
+		t.Fatalf("got %q, want %q", got, "[]")
+	}
+}
+
+func TestWriteFileAtomicOverwritesExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	if err := os.WriteFile(path, []byte("stale"), 0o644); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+
+	if err := WriteFileAtomic(path, []byte("fresh"), 0o644); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "fresh" {
+		t.Fatalf("got %q, want %q", got, "fresh")
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries in temp dir, want 1 (no leftover temp file)", len(entries))
+	}
+}
@@ -0,0 +1,62 @@
+package pathutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultDBPathPrefersMusicApp(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	mustMkdir(t, filepath.Join(home, "Music", "Music"))
+	mustMkdir(t, filepath.Join(home, "Music", "iTunes"))
+
+	got, err := DefaultDBPath()
+	if err != nil {
+		t.Fatalf("DefaultDBPath: %v", err)
+	}
+	want := filepath.Join(home, "Music", "Music", "itunes_library.db")
+	if got != want {
+		t.Fatalf("got %q, want %q (Music.app folder should win when both exist)", got, want)
+	}
+}
+
+func TestDefaultDBPathFallsBackToLegacyiTunesFolder(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	mustMkdir(t, filepath.Join(home, "Music", "iTunes"))
+
+	got, err := DefaultDBPath()
+	if err != nil {
+		t.Fatalf("DefaultDBPath: %v", err)
+	}
+	want := filepath.Join(home, "Music", "iTunes", "itunes_library.db")
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDefaultDBPathFallsBackToMusicRoot(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	// Neither Music/Music nor Music/iTunes exists.
+
+	got, err := DefaultDBPath()
+	if err != nil {
+		t.Fatalf("DefaultDBPath: %v", err)
+	}
+	want := filepath.Join(home, "Music", "itunes_library.db")
+	if got != want {
+		t.Fatalf("got %q, want %q (should not bury the db in a folder that doesn't exist)", got, want)
+	}
+}
+
+func mustMkdir(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("MkdirAll(%q): %v", dir, err)
+	}
+}
@@ -0,0 +1,87 @@
+// Package pathutil holds small filesystem path helpers shared by the
+// database, itunes, and migrate packages, so each doesn't carry its own
+// copy of the same "~" expansion logic.
+package pathutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandPath resolves a leading "~" to the current user's home directory.
+// It returns an error if expansion is required but the home directory
+// can't be determined, rather than silently handing back the unexpanded
+// "~" path.
+func ExpandPath(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("pathutil: expand path %q: %w", path, err)
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+// defaultDBFilename is the library cache database's filename within
+// whichever directory DefaultDBPath resolves to.
+const defaultDBFilename = "itunes_library.db"
+
+// defaultDBDirCandidates are directories DefaultDBPath checks, in order,
+// for being the user's actual media library location: the modern Music.app
+// folder, then the legacy iTunes.app one. Whichever exists first wins,
+// since that's a better signal for where this user's media actually lives
+// than guessing based on macOS version.
+var defaultDBDirCandidates = []string{
+	"Music/Music",
+	"Music/iTunes",
+}
+
+// DefaultDBPath picks a sensible default location for the library cache
+// database when no explicit path or ITUNES_DB_PATH is configured: whichever
+// of the modern Music.app or legacy iTunes.app folders under the user's
+// home directory already exists, or ~/Music itself if neither does, so the
+// cache isn't buried in a legacy folder this user may never have had.
+func DefaultDBPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("pathutil: resolve default db path: %w", err)
+	}
+	for _, rel := range defaultDBDirCandidates {
+		dir := filepath.Join(home, rel)
+		if info, statErr := os.Stat(dir); statErr == nil && info.IsDir() {
+			return filepath.Join(dir, defaultDBFilename), nil
+		}
+	}
+	return filepath.Join(home, "Music", defaultDBFilename), nil
+}
+
+// cacheDirEnvVar names the environment variable overriding the default
+// cache directory. $TMPDIR gets cleared on reboot, which loses the
+// refresh cache, so anyone relying on it across reboots should point this
+// somewhere durable.
+const cacheDirEnvVar = "ITUNES_CACHE_DIR"
+
+// ResolveCacheDir determines the cache directory honoring, in order: an
+// explicit argument, the ITUNES_CACHE_DIR environment variable, then
+// os.TempDir()/itunes-cache. It creates the directory if missing so
+// callers can write to it immediately.
+func ResolveCacheDir(explicit string) (string, error) {
+	dir := explicit
+	if dir == "" {
+		dir = os.Getenv(cacheDirEnvVar)
+	}
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "itunes-cache")
+	}
+	dir, err := ExpandPath(dir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("pathutil: create cache directory %q: %w", dir, err)
+	}
+	return dir, nil
+}
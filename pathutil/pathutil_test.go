@@ -0,0 +1,73 @@
+package pathutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandPathTilde(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory available: %v", err)
+	}
+	got, err := ExpandPath("~/Music/library.db")
+	if err != nil {
+		t.Fatalf("ExpandPath: %v", err)
+	}
+	want := filepath.Join(home, "Music/library.db")
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestExpandPathAbsolute(t *testing.T) {
+	got, err := ExpandPath("/var/lib/itunes/library.db")
+	if err != nil {
+		t.Fatalf("ExpandPath: %v", err)
+	}
+	if got != "/var/lib/itunes/library.db" {
+		t.Fatalf("got %q, want unchanged absolute path", got)
+	}
+}
+
+func TestExpandPathRelative(t *testing.T) {
+	got, err := ExpandPath("library.db")
+	if err != nil {
+		t.Fatalf("ExpandPath: %v", err)
+	}
+	if got != "library.db" {
+		t.Fatalf("got %q, want unchanged relative path", got)
+	}
+}
+
+func TestResolveCacheDirEnvOverride(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "itunes-cache-override")
+	t.Setenv("ITUNES_CACHE_DIR", dir)
+
+	got, err := ResolveCacheDir("")
+	if err != nil {
+		t.Fatalf("ResolveCacheDir: %v", err)
+	}
+	if got != dir {
+		t.Fatalf("got %q, want %q", got, dir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Fatalf("cache dir %q was not created: %v", dir, err)
+	}
+}
+
+func TestResolveCacheDirCreatesMissingDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "cache")
+
+	got, err := ResolveCacheDir(dir)
+	if err != nil {
+		t.Fatalf("ResolveCacheDir: %v", err)
+	}
+	if got != dir {
+		t.Fatalf("got %q, want %q", got, dir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Fatalf("cache dir %q was not created: %v", dir, err)
+	}
+}
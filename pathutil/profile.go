@@ -0,0 +1,133 @@
+package pathutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// configDirEnvVar names the environment variable overriding the default
+// config directory, where profile databases and the active-profile marker
+// live.
+const configDirEnvVar = "ITUNES_CONFIG_DIR"
+
+// activeProfileFilename is the file within the config directory recording
+// the currently active profile's name, one line, no trailing structure.
+const activeProfileFilename = "active_profile"
+
+// ErrUnknownProfile is returned by SetActiveProfile when the named profile
+// has no database file yet. Requiring the file to already exist catches a
+// typo'd profile name before it's written down as the new default, rather
+// than silently switching to what would be a brand new empty library.
+var ErrUnknownProfile = errors.New("pathutil: unknown profile")
+
+// ResolveConfigDir determines the config directory honoring, in order: an
+// explicit argument, the ITUNES_CONFIG_DIR environment variable, then
+// ~/.config/itunes. It creates the directory if missing so callers can
+// write to it immediately.
+func ResolveConfigDir(explicit string) (string, error) {
+	dir := explicit
+	if dir == "" {
+		dir = os.Getenv(configDirEnvVar)
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config", "itunes")
+	}
+	dir, err := ExpandPath(dir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// ProfileDBPath returns the database file path for the named profile:
+// <configDir>/<name>.db. It does not check whether the file exists.
+func ProfileDBPath(name string) (string, error) {
+	dir, err := ResolveConfigDir("")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".db"), nil
+}
+
+// ListProfiles returns the names of every profile with a database file
+// under the config directory, sorted for stable output.
+func ListProfiles() ([]string, error) {
+	dir, err := ResolveConfigDir("")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".db" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".db"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ActiveProfile returns the name of the profile set by SetActiveProfile, or
+// "" if none has been set.
+func ActiveProfile() (string, error) {
+	dir, err := ResolveConfigDir("")
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, activeProfileFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SetActiveProfile records name as the active profile, so a later
+// ResolveDBPath with no explicit path or ITUNES_DB_PATH opens that
+// profile's database. It fails with ErrUnknownProfile if name has no
+// database file yet under the config directory.
+func SetActiveProfile(name string) error {
+	exists, err := profileExists(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUnknownProfile
+	}
+	dir, err := ResolveConfigDir("")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(filepath.Join(dir, activeProfileFilename), []byte(name), 0o644)
+}
+
+func profileExists(name string) (bool, error) {
+	path, err := ProfileDBPath(name)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
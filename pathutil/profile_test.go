@@ -0,0 +1,93 @@
+package pathutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListProfilesReturnsSortedDBNames(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv(configDirEnvVar, configDir)
+
+	for _, name := range []string{"work", "home", "laptop"} {
+		path, err := ProfileDBPath(name)
+		if err != nil {
+			t.Fatalf("ProfileDBPath(%q): %v", name, err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("seed %q: %v", path, err)
+		}
+	}
+	// A non-.db file in the config dir should be ignored.
+	if err := os.WriteFile(filepath.Join(configDir, activeProfileFilename), []byte("home"), 0o644); err != nil {
+		t.Fatalf("seed active profile marker: %v", err)
+	}
+
+	got, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles: %v", err)
+	}
+	want := []string{"home", "laptop", "work"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSetActiveProfileRejectsUnknownProfile(t *testing.T) {
+	t.Setenv(configDirEnvVar, t.TempDir())
+
+	err := SetActiveProfile("nonexistent")
+	if !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("SetActiveProfile error = %v, want ErrUnknownProfile", err)
+	}
+
+	active, err := ActiveProfile()
+	if err != nil {
+		t.Fatalf("ActiveProfile: %v", err)
+	}
+	if active != "" {
+		t.Fatalf("ActiveProfile = %q, want empty after a rejected SetActiveProfile", active)
+	}
+}
+
+func TestSetActiveProfileThenActiveProfileRoundTrips(t *testing.T) {
+	t.Setenv(configDirEnvVar, t.TempDir())
+
+	path, err := ProfileDBPath("work")
+	if err != nil {
+		t.Fatalf("ProfileDBPath: %v", err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("seed profile db: %v", err)
+	}
+
+	if err := SetActiveProfile("work"); err != nil {
+		t.Fatalf("SetActiveProfile: %v", err)
+	}
+	got, err := ActiveProfile()
+	if err != nil {
+		t.Fatalf("ActiveProfile: %v", err)
+	}
+	if got != "work" {
+		t.Fatalf("ActiveProfile = %q, want %q", got, "work")
+	}
+}
+
+func TestActiveProfileEmptyWhenNeverSet(t *testing.T) {
+	t.Setenv(configDirEnvVar, t.TempDir())
+
+	got, err := ActiveProfile()
+	if err != nil {
+		t.Fatalf("ActiveProfile: %v", err)
+	}
+	if got != "" {
+		t.Fatalf("ActiveProfile = %q, want empty", got)
+	}
+}